@@ -0,0 +1,122 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// tokenExpiryMargin is how long before its expiry a token of a TokenProvider
+// is replaced.
+const tokenExpiryMargin = 5 * time.Minute
+
+// TokenProvider gets the access tokens authorizing the requests, e.g. from a
+// custom OAuth flow or a secret store, along with the time they expire at. A
+// zero expiry means unknown: the token is then used for a single request. A
+// BearerAuthorizer calls it with the context of the request needing a token.
+type TokenProvider interface {
+	Token(ctx context.Context) (token string, expiresOn time.Time, err error)
+}
+
+// TokenProviderFunc is a function implementing TokenProvider.
+type TokenProviderFunc func(ctx context.Context) (string, time.Time, error)
+
+// Token implements TokenProvider.
+func (f TokenProviderFunc) Token(ctx context.Context) (string, time.Time, error) {
+	return f(ctx)
+}
+
+// BearerAuthorizer authorizes the requests with the bearer tokens of a
+// TokenProvider, to set as the Authorizer of the clients. Each token is
+// reused until five minutes before it expires, or until a request fails
+// with 401 Unauthorized: ManagementClient.Do then sends the request once
+// more with a new token. It is safe for concurrent use, and a burst of
+// requests needing a new token gets a single one from the provider.
+//
+// The adal package provides the tokens of a service principal or a managed
+// identity as an adal.OAuthTokenProvider, for autorest.NewBearerAuthorizer
+// rather than this type; e.g. on an Azure VM with a managed identity:
+//
+//	spt, err := adal.NewServicePrincipalTokenFromMSI(*oauthConfig, logic.DefaultCLIResource)
+//	if err != nil {
+//		// ...
+//	}
+//	client.Authorizer = autorest.NewBearerAuthorizer(spt)
+type BearerAuthorizer struct {
+	provider TokenProvider
+
+	mu        sync.Mutex
+	token     string
+	expiresOn time.Time
+}
+
+// NewBearerAuthorizer creates an authorizer using the tokens of provider.
+func NewBearerAuthorizer(provider TokenProvider) *BearerAuthorizer {
+	return &BearerAuthorizer{provider: provider}
+}
+
+// WithAuthorization implements autorest.Authorizer. The returned decorator
+// fails if no token can be got for the request.
+func (a *BearerAuthorizer) WithAuthorization() autorest.PrepareDecorator {
+	return func(p autorest.Preparer) autorest.Preparer {
+		return autorest.PreparerFunc(func(r *http.Request) (*http.Request, error) {
+			r, err := p.Prepare(r)
+			if err != nil {
+				return r, err
+			}
+			token, err := a.get(r.Context())
+			if err != nil {
+				return r, autorest.NewErrorWithError(err, "logic.BearerAuthorizer", "WithAuthorization", nil, "Failed to get a token for request to %s", r.URL)
+			}
+			return autorest.Prepare(r, autorest.WithBearerAuthorization(token))
+		})
+	}
+}
+
+// get returns the cached token, or a new one from the provider if it is
+// about to expire. The lock is held while the provider is called, so that
+// the concurrent requests wait for its token instead of getting their own.
+func (a *BearerAuthorizer) get(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token != "" && time.Until(a.expiresOn) > tokenExpiryMargin {
+		return a.token, nil
+	}
+	token, expiresOn, err := a.provider.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", errors.New("logic: empty access token")
+	}
+	a.token, a.expiresOn = token, expiresOn
+	return token, nil
+}
+
+// invalidate drops the cached token if it is the given one, which the
+// service rejected, so that the next request gets a new one. The token is
+// kept if another request has replaced it already.
+func (a *BearerAuthorizer) invalidate(token string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token == token {
+		a.token, a.expiresOn = "", time.Time{}
+	}
+}
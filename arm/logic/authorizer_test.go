@@ -0,0 +1,129 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBearerAuthorizer(t *testing.T) {
+	var authorizations []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var calls int
+	expiresIn := time.Hour
+	provider := TokenProviderFunc(func(ctx context.Context) (string, time.Time, error) {
+		if ctx == nil {
+			t.Error("got a nil context")
+		}
+		calls++
+		return fmt.Sprintf("token%d", calls), time.Now().Add(expiresIn), nil
+	})
+	client := NewWorkflowsClientWithBaseURI(srv.URL, "subscription")
+	client.Authorizer = NewBearerAuthorizer(provider)
+	for i := 0; i < 2; i++ {
+		if _, err := client.Get("group", "workflow"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A token about to expire is replaced.
+	expiresIn = time.Minute
+	client.Authorizer = NewBearerAuthorizer(provider)
+	for i := 0; i < 2; i++ {
+		if _, err := client.Get("group", "workflow"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{"Bearer token1", "Bearer token1", "Bearer token2", "Bearer token3"}
+	if fmt.Sprint(authorizations) != fmt.Sprint(want) {
+		t.Fatalf("got Authorization headers %q, want %q", authorizations, want)
+	}
+
+	// A failure to get a token is not worth waiting for the retries.
+	errNoToken := errors.New("no token")
+	client.RetryPolicy = nil
+	client.Authorizer = NewBearerAuthorizer(TokenProviderFunc(func(context.Context) (string, time.Time, error) {
+		return "", time.Time{}, errNoToken
+	}))
+	if _, err := client.Get("group", "workflow"); !errors.Is(UnwrapError(err), errNoToken) {
+		t.Fatalf("got error %v, want %v", err, errNoToken)
+	}
+	if len(authorizations) != len(want) {
+		t.Fatal("the request was sent without a token")
+	}
+}
+
+func TestBearerAuthorizerRefreshOnUnauthorized(t *testing.T) {
+	var mu sync.Mutex
+	valid := "token1"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		ok := r.Header.Get("Authorization") == "Bearer "+valid
+		mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method == http.MethodPut && string(body) != `{"name":"workflow"}` {
+			t.Errorf("got body %q after the refresh", body)
+		}
+		w.Write([]byte(`{"name":"workflow"}`))
+	}))
+	defer srv.Close()
+
+	var calls int
+	provider := TokenProviderFunc(func(context.Context) (string, time.Time, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return fmt.Sprintf("token%d", calls), time.Now().Add(time.Hour), nil
+	})
+	client := NewWorkflowsClientWithBaseURI(srv.URL, "subscription")
+	client.Authorizer = NewBearerAuthorizer(provider)
+	if _, err := client.Get("group", "workflow"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The token is revoked: a burst of requests gets a single new one.
+	mu.Lock()
+	valid = "token2"
+	mu.Unlock()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := "workflow"
+			if _, err := client.CreateOrUpdate("group", "workflow", Workflow{Name: &name}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 2 {
+		t.Fatalf("got %d tokens, want 2", calls)
+	}
+
+	// A request is sent again only once.
+	mu.Lock()
+	valid = "none"
+	mu.Unlock()
+	if _, err := client.Get("group", "workflow"); err == nil {
+		t.Fatal("expected the request to fail with 401 Unauthorized")
+	}
+	if calls != 3 {
+		t.Fatalf("got %d tokens, want 3", calls)
+	}
+}
@@ -0,0 +1,94 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// RedactFunc returns the value to log in place of the member key of a JSON
+// object in a request body, e.g. "<redacted>" for a secret. It is called for
+// the members of the nested objects too.
+type RedactFunc func(key string, value interface{}) interface{}
+
+// LogRequestBodies returns a decorator logging the JSON body of every
+// request with logf, pretty-printed and passed through redact, if not nil.
+// The body sent is left untouched, i.e. compact. Set it as the
+// RequestInspector of a client to debug the requests it sends:
+//
+//	client.RequestInspector = logic.LogRequestBodies(log.Printf, func(key string, value interface{}) interface{} {
+//		if key == "password" {
+//			return "<redacted>"
+//		}
+//		return value
+//	})
+//
+// The bodies which are not JSON are logged as they are.
+func LogRequestBodies(logf func(format string, args ...interface{}), redact RedactFunc) autorest.PrepareDecorator {
+	return func(p autorest.Preparer) autorest.Preparer {
+		return autorest.PreparerFunc(func(r *http.Request) (*http.Request, error) {
+			r, err := p.Prepare(r)
+			if err != nil || r.Body == nil {
+				return r, err
+			}
+			body, err := ioutil.ReadAll(r.Body)
+			r.Body.Close()
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			if err != nil {
+				return r, err
+			}
+			logf("logic: %s body:\n%s", describeRequest(r), prettyBody(body, redact))
+			return r, nil
+		})
+	}
+}
+
+// prettyBody returns body indented and redacted, if it is JSON.
+func prettyBody(body []byte, redact RedactFunc) []byte {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+	if redact != nil {
+		v = redactValue(v, redact)
+	}
+	var pretty bytes.Buffer
+	enc := json.NewEncoder(&pretty)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return body
+	}
+	return bytes.TrimSuffix(pretty.Bytes(), []byte("\n"))
+}
+
+func redactValue(v interface{}, redact RedactFunc) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = redact(key, redactValue(value, redact))
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value, redact)
+		}
+	}
+	return v
+}
@@ -0,0 +1,62 @@
+package logic
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogRequestBodies(t *testing.T) {
+	var sent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		sent = string(b)
+		fmt.Fprint(w, `{"name":"workflow"}`)
+	}))
+	defer srv.Close()
+
+	var logged string
+	client := NewWorkflowsClientWithBaseURI(srv.URL, "subscription")
+	client.RequestInspector = LogRequestBodies(func(format string, args ...interface{}) {
+		logged = fmt.Sprintf(format, args...)
+	}, func(key string, value interface{}) interface{} {
+		if key == "secret" {
+			return "<redacted>"
+		}
+		return value
+	})
+
+	location, secret := "westus", "s3cr3t"
+	workflow := Workflow{Location: &location, Tags: &map[string]*string{"secret": &secret}}
+	if _, err := client.CreateOrUpdate("group", "workflow", workflow); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sent, "\n") || !strings.Contains(sent, secret) {
+		t.Fatalf("sent %q, want the compact, unredacted JSON", sent)
+	}
+	if !strings.Contains(logged, "PUT ") || !strings.Contains(logged, "\n  \"location\": \"westus\"") {
+		t.Fatalf("logged %q, want the pretty-printed body", logged)
+	}
+	if strings.Contains(logged, secret) {
+		t.Fatalf("logged %q, want the secret redacted", logged)
+	}
+}
+
+func TestPrettyBodyRedact(t *testing.T) {
+	redact := func(key string, value interface{}) interface{} {
+		if key == "password" {
+			return "<redacted>"
+		}
+		return value
+	}
+	got := string(prettyBody([]byte(`{"a":[{"password":"p","user":"u"}]}`), redact))
+	if strings.Contains(got, `"p"`) || !strings.Contains(got, `"<redacted>"`) || !strings.Contains(got, `"u"`) {
+		t.Fatalf("got %s, want the nested password redacted", got)
+	}
+	if got := string(prettyBody([]byte("not json"), redact)); got != "not json" {
+		t.Fatalf("got %q, want a non-JSON body unchanged", got)
+	}
+}
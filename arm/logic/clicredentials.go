@@ -0,0 +1,236 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+const (
+	// DefaultCLIResource is the resource CLITokenProvider gets tokens for
+	// by default, i.e. Azure Resource Manager.
+	DefaultCLIResource = DefaultBaseURI + "/"
+
+	// cliExpiryMargin is how long before its expiry a token is refreshed.
+	cliExpiryMargin = 5 * time.Minute
+
+	// cliTimeLayout is the layout of the local expiry time written by the
+	// Azure CLI.
+	cliTimeLayout = "2006-01-02 15:04:05.999999"
+)
+
+// ErrNoCLIToken is returned when neither the Azure CLI token cache nor the
+// CLI itself provide a token, e.g. because "az login" has not been run.
+var ErrNoCLIToken = errors.New("logic: no Azure CLI access token, run \"az login\"")
+
+// CLITokenProvider provides the access tokens of an Azure CLI session, so
+// that the clients can be used after "az login" without a service
+// principal. It reads the token cache of the CLI and, once the cached token
+// is about to expire, asks the CLI for a new one with
+// "az account get-access-token", which refreshes it if needed.
+//
+// CLITokenProvider implements adal.OAuthTokenProvider and adal.Refresher and
+// is safe for concurrent use. Use NewCLIAuthorizer to authorize clients.
+type CLITokenProvider struct {
+	// Resource is the resource the tokens are for.
+	Resource string
+
+	// TokenFile is the token cache of the CLI. It defaults to
+	// ~/.azure/accessTokens.json, or accessTokens.json under the directory
+	// named by the AZURE_CONFIG_DIR environment variable.
+	TokenFile string
+
+	// getToken runs the CLI; it is replaced by the tests.
+	getToken func(resource string) ([]byte, error)
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// cliToken is a token, as found in the token cache of the CLI or printed by
+// "az account get-access-token".
+type cliToken struct {
+	AccessToken string `json:"accessToken"`
+	Resource    string `json:"resource"`
+	ExpiresOn   string `json:"expiresOn"`
+
+	// ExpiresOnUnix is printed only by the newer versions of the CLI.
+	ExpiresOnUnix int64 `json:"expires_on"`
+}
+
+// NewCLITokenProvider creates a provider for the tokens for resource, or
+// DefaultCLIResource if resource is empty.
+func NewCLITokenProvider(resource string) *CLITokenProvider {
+	if resource == "" {
+		resource = DefaultCLIResource
+	}
+	return &CLITokenProvider{Resource: resource}
+}
+
+// NewCLIAuthorizer creates a bearer authorizer using the Azure CLI session,
+// to set as the Authorizer of the clients. It fails if no token is
+// available.
+func NewCLIAuthorizer() (autorest.Authorizer, error) {
+	provider := NewCLITokenProvider("")
+	if err := provider.EnsureFresh(); err != nil {
+		return nil, err
+	}
+	return autorest.NewBearerAuthorizer(provider), nil
+}
+
+// OAuthToken returns the current access token.
+func (p *CLITokenProvider) OAuthToken() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.token
+}
+
+// EnsureFresh gets a new token if the current one expires within five
+// minutes, from the token cache if it holds a fresher one, or else from
+// the CLI.
+func (p *CLITokenProvider) EnsureFresh() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.fresh() {
+		return nil
+	}
+	if err := p.load(); err == nil && p.fresh() {
+		return nil
+	}
+	return p.refresh()
+}
+
+// Refresh gets a new token from the CLI.
+func (p *CLITokenProvider) Refresh() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.refresh()
+}
+
+// RefreshExchange gets a new token for resource from the CLI and makes it
+// the provider's resource.
+func (p *CLITokenProvider) RefreshExchange(resource string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Resource = resource
+	return p.refresh()
+}
+
+func (p *CLITokenProvider) fresh() bool {
+	return p.token != "" && time.Now().Add(cliExpiryMargin).Before(p.expires)
+}
+
+// load reads the token for the resource expiring last from the token cache.
+func (p *CLITokenProvider) load() error {
+	file := p.TokenFile
+	if file == "" {
+		dir := os.Getenv("AZURE_CONFIG_DIR")
+		if dir == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return err
+			}
+			dir = filepath.Join(home, ".azure")
+		}
+		file = filepath.Join(dir, "accessTokens.json")
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var tokens []cliToken
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return fmt.Errorf("logic: invalid Azure CLI token cache %s: %w", file, err)
+	}
+
+	found := false
+	for _, token := range tokens {
+		if !sameResource(token.Resource, p.Resource) {
+			continue
+		}
+		expires, err := token.expires()
+		if err != nil || token.AccessToken == "" {
+			continue
+		}
+		if !found || expires.After(p.expires) {
+			p.token, p.expires, found = token.AccessToken, expires, true
+		}
+	}
+	if !found {
+		return ErrNoCLIToken
+	}
+	return nil
+}
+
+// refresh gets a new token from the CLI.
+func (p *CLITokenProvider) refresh() error {
+	getToken := p.getToken
+	if getToken == nil {
+		getToken = runCLI
+	}
+	out, err := getToken(p.Resource)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNoCLIToken, err)
+	}
+	var token cliToken
+	if err := json.Unmarshal(out, &token); err != nil {
+		return fmt.Errorf("logic: invalid Azure CLI output: %w", err)
+	}
+	expires, err := token.expires()
+	if err != nil {
+		return err
+	}
+	if token.AccessToken == "" || !time.Now().Before(expires) {
+		return ErrNoCLIToken
+	}
+	p.token, p.expires = token.AccessToken, expires
+	return nil
+}
+
+func runCLI(resource string) ([]byte, error) {
+	out, err := exec.Command("az", "account", "get-access-token", "--resource", resource, "--output", "json").Output()
+	if e, ok := err.(*exec.ExitError); ok && len(e.Stderr) != 0 {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(e.Stderr)))
+	}
+	return out, err
+}
+
+func (t cliToken) expires() (time.Time, error) {
+	if t.ExpiresOnUnix != 0 {
+		return time.Unix(t.ExpiresOnUnix, 0), nil
+	}
+	expires, err := time.ParseInLocation(cliTimeLayout, t.ExpiresOn, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("logic: invalid Azure CLI token expiry %q: %w", t.ExpiresOn, err)
+	}
+	return expires, nil
+}
+
+// sameResource compares resource URIs, ignoring a trailing slash.
+func sameResource(a, b string) bool {
+	return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
@@ -0,0 +1,52 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCLITokenProvider(t *testing.T) {
+	local := func(d time.Duration) string {
+		return time.Now().Add(d).Format(cliTimeLayout)
+	}
+	file := filepath.Join(t.TempDir(), "accessTokens.json")
+	cache := fmt.Sprintf(`[
+		{"accessToken":"expired","resource":"https://management.azure.com/","expiresOn":%q},
+		{"accessToken":"cached","resource":"https://management.azure.com","expiresOn":%q},
+		{"accessToken":"other","resource":"https://graph.windows.net/","expiresOn":%q}
+	]`, local(-time.Hour), local(time.Hour), local(2*time.Hour))
+	if err := ioutil.WriteFile(file, []byte(cache), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var refreshes int
+	p := NewCLITokenProvider("")
+	p.TokenFile = file
+	p.getToken = func(resource string) ([]byte, error) {
+		refreshes++
+		if resource != DefaultCLIResource {
+			t.Errorf("got a token requested for %s, want %s", resource, DefaultCLIResource)
+		}
+		return []byte(fmt.Sprintf(`{"accessToken":"refreshed","expires_on":%d}`, time.Now().Add(time.Hour).Unix())), nil
+	}
+
+	if err := p.EnsureFresh(); err != nil || p.OAuthToken() != "cached" || refreshes != 0 {
+		t.Fatalf("EnsureFresh()=%v, got token %q after %d refreshes, want the cached one", err, p.OAuthToken(), refreshes)
+	}
+
+	// A token about to expire is refreshed through the CLI.
+	p.expires = time.Now().Add(time.Minute)
+	p.TokenFile = filepath.Join(t.TempDir(), "missing.json")
+	if err := p.EnsureFresh(); err != nil || p.OAuthToken() != "refreshed" || refreshes != 1 {
+		t.Fatalf("EnsureFresh()=%v, got token %q after %d refreshes, want a refreshed one", err, p.OAuthToken(), refreshes)
+	}
+
+	p.getToken = func(string) ([]byte, error) { return nil, errors.New("az: not logged in") }
+	if err := p.Refresh(); !errors.Is(err, ErrNoCLIToken) {
+		t.Fatalf("got error %v, want %v", err, ErrNoCLIToken)
+	}
+}
@@ -22,21 +22,66 @@ package logic
 // regenerated.
 
 import (
+	"errors"
 	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/azure"
 	"net/http"
 )
 
 const (
 	// DefaultBaseURI is the default URI used for the service Logic
 	DefaultBaseURI = "https://management.azure.com"
+
+	// DefaultAPIVersion is the default API version used for the service Logic
+	DefaultAPIVersion = "2016-06-01"
+
+	// DefaultMaxPages is the default maximum number of pages the pagination
+	// helpers, such as WorkflowRunsClient.ListComplete, fetch.
+	DefaultMaxPages = 1000
 )
 
 // ManagementClient is the base client for Logic.
+//
+// The requests are authorized by the Authorizer of the embedded
+// autorest.Client, which is applied by Do to every request: set it to e.g.
+// the result of NewCLIAuthorizer, a BearerAuthorizer with a custom
+// TokenProvider, or an autorest.BearerAuthorizer of an adal token, such as
+// that of a managed identity.
+//
+// Every method has an exported Preparer, e.g. WorkflowsClient.GetPreparer,
+// returning the request the method would send, without sending it: its
+// method, URL and body can be inspected or logged, e.g. for an approval
+// step, then sent with the matching Sender and read with the Responder.
+// The Authorization and User-Agent headers are only set once it is sent.
 type ManagementClient struct {
 	autorest.Client
 	BaseURI        string
 	SubscriptionID string
+
+	// APIVersion is the api-version sent with each request. It defaults to
+	// DefaultAPIVersion and may be overridden to pin a different version.
+	APIVersion string
+
+	// MaxPages limits the number of pages the pagination helpers fetch
+	// before failing with ErrTooManyPages. Zero means DefaultMaxPages.
+	MaxPages int
+
+	// ETagCache, if set, makes WorkflowRunsClient.Get send If-None-Match with
+	// the ETag of the cached run and return the cached run on a 304 Not
+	// Modified. It is nil, i.e. caching is disabled, by default.
+	ETagCache *ETagCache
+
+	// RetryPolicy decides which failed requests are sent again, see Do. It
+	// defaults to DefaultRetryPolicy with autorest.DefaultRetryAttempts
+	// retries. If nil, the requests are retried by the autorest.Client as
+	// configured by its RetryAttempts.
+	RetryPolicy RetryPolicy
+
+	// CorrelationRequestID, if set, is sent in the CorrelationRequestIDHeader
+	// of the requests which do not carry one, so that a batch of calls, e.g.
+	// by the clients of a ClientSet, can be traced end to end under a
+	// single ID. Otherwise the service assigns one to each request; read it
+	// with CorrelationRequestID.
+	CorrelationRequestID string
 }
 
 // New creates an instance of the ManagementClient client.
@@ -50,7 +95,21 @@ func NewWithBaseURI(baseURI string, subscriptionID string) ManagementClient {
 		Client:         autorest.NewClientWithUserAgent(UserAgent()),
 		BaseURI:        baseURI,
 		SubscriptionID: subscriptionID,
+		APIVersion:     DefaultAPIVersion,
+		RetryPolicy:    DefaultRetryPolicy{MaxRetries: autorest.DefaultRetryAttempts},
+	}
+}
+
+// NewWithAPIVersion creates an instance of the ManagementClient client that
+// sends apiVersion instead of DefaultAPIVersion. apiVersion must not be
+// empty.
+func NewWithAPIVersion(baseURI string, subscriptionID string, apiVersion string) (ManagementClient, error) {
+	if apiVersion == "" {
+		return ManagementClient{}, errors.New("logic: API version required")
 	}
+	client := NewWithBaseURI(baseURI, subscriptionID)
+	client.APIVersion = apiVersion
+	return client, nil
 }
 
 // ListOperations lists all of the available Logic REST API operations.
@@ -64,13 +123,13 @@ func (client ManagementClient) ListOperations() (result OperationListResult, err
 	resp, err := client.ListOperationsSender(req)
 	if err != nil {
 		result.Response = autorest.Response{Response: resp}
-		err = autorest.NewErrorWithError(err, "logic.ManagementClient", "ListOperations", resp, "Failure sending request")
+		err = autorest.NewErrorWithError(err, "logic.ManagementClient", "ListOperations", resp, "Failure sending request: %s", describeRequest(req))
 		return
 	}
 
 	result, err = client.ListOperationsResponder(resp)
 	if err != nil {
-		err = autorest.NewErrorWithError(err, "logic.ManagementClient", "ListOperations", resp, "Failure responding to request")
+		err = autorest.NewErrorWithError(err, "logic.ManagementClient", "ListOperations", resp, "Failure responding to request: %s", describeRequest(req))
 	}
 
 	return
@@ -78,9 +137,8 @@ func (client ManagementClient) ListOperations() (result OperationListResult, err
 
 // ListOperationsPreparer prepares the ListOperations request.
 func (client ManagementClient) ListOperationsPreparer() (*http.Request, error) {
-	const APIVersion = "2016-06-01"
 	queryParameters := map[string]interface{}{
-		"api-version": APIVersion,
+		"api-version": client.APIVersion,
 	}
 
 	preparer := autorest.CreatePreparer(
@@ -103,7 +161,7 @@ func (client ManagementClient) ListOperationsResponder(resp *http.Response) (res
 	err = autorest.Respond(
 		resp,
 		client.ByInspecting(),
-		azure.WithErrorUnlessStatusCode(http.StatusOK),
+		withErrorUnlessStatusCode(http.StatusOK),
 		autorest.ByUnmarshallingJSON(&result),
 		autorest.ByClosing())
 	result.Response = autorest.Response{Response: resp}
@@ -123,12 +181,12 @@ func (client ManagementClient) ListOperationsNextResults(lastResults OperationLi
 	resp, err := client.ListOperationsSender(req)
 	if err != nil {
 		result.Response = autorest.Response{Response: resp}
-		return result, autorest.NewErrorWithError(err, "logic.ManagementClient", "ListOperations", resp, "Failure sending next results request")
+		return result, autorest.NewErrorWithError(err, "logic.ManagementClient", "ListOperations", resp, "Failure sending next results request: %s", describeRequest(req))
 	}
 
 	result, err = client.ListOperationsResponder(resp)
 	if err != nil {
-		err = autorest.NewErrorWithError(err, "logic.ManagementClient", "ListOperations", resp, "Failure responding to next results request")
+		err = autorest.NewErrorWithError(err, "logic.ManagementClient", "ListOperations", resp, "Failure responding to next results request: %s", describeRequest(req))
 	}
 
 	return
@@ -0,0 +1,87 @@
+package logic
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIVersion(t *testing.T) {
+	var versions []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		versions = append(versions, r.URL.Query().Get("api-version"))
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	if client := NewWithBaseURI(srv.URL, "subscription"); client.APIVersion != DefaultAPIVersion {
+		t.Fatalf("got APIVersion %q, want %q", client.APIVersion, DefaultAPIVersion)
+	}
+	overridden, err := NewWithAPIVersion(srv.URL, "subscription", "2099-01-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, testCase := range []struct {
+		client ManagementClient
+		want   string
+	}{
+		{NewWithBaseURI(srv.URL, "subscription"), DefaultAPIVersion},
+		{overridden, "2099-01-01"},
+	} {
+		client := testCase.client
+		calls := map[string]func() error{
+			"ListOperations":         func() error { _, err := client.ListOperations(); return err },
+			"AgreementsClient":       func() error { _, err := AgreementsClient{client}.Get("group", "account", "agreement"); return err },
+			"IntegrationAccounts":    func() error { _, err := IntegrationAccountsClient{client}.Get("group", "account"); return err },
+			"SchemasClient":          func() error { _, err := SchemasClient{client}.Get("group", "account", "schema"); return err },
+			"WorkflowsClient":        func() error { _, err := WorkflowsClient{client}.Get("group", "workflow"); return err },
+			"WorkflowRunsClient":     func() error { _, err := WorkflowRunsClient{client}.List("group", "workflow", nil, ""); return err },
+			"WorkflowTriggersClient": func() error { _, err := WorkflowTriggersClient{client}.Get("group", "workflow", "trigger"); return err },
+			"WorkflowVersionsClient": func() error { _, err := WorkflowVersionsClient{client}.Get("group", "workflow", "1"); return err },
+		}
+		for name, call := range calls {
+			versions = nil
+			if err := call(); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if len(versions) != 1 || versions[0] != testCase.want {
+				t.Errorf("%s: got api-version %q, want %q", name, versions, testCase.want)
+			}
+		}
+	}
+
+	if _, err := NewWithAPIVersion(srv.URL, "subscription", ""); err == nil {
+		t.Fatal("expected an error for an empty API version")
+	}
+}
+
+func TestPreparer(t *testing.T) {
+	client := NewWorkflowsClientWithBaseURI("https://management.example.com", "subscription")
+	name := "workflow"
+	req, err := client.CreateOrUpdatePreparer("group", "workflow", Workflow{Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if req.Method != http.MethodPut {
+		t.Fatalf("got method %s, want PUT", req.Method)
+	}
+	if want := "/subscriptions/subscription/resourceGroups/group/providers/Microsoft.Logic/workflows/workflow"; req.URL.Host != "management.example.com" || req.URL.Path != want {
+		t.Fatalf("got URL %s, want the path %s", req.URL, want)
+	}
+	if version := req.URL.Query().Get("api-version"); version != DefaultAPIVersion {
+		t.Fatalf("got api-version %q, want %q", version, DefaultAPIVersion)
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `{"name":"workflow"}` {
+		t.Fatalf("got body %s", body)
+	}
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		t.Fatalf("got Authorization %q before the request is sent", auth)
+	}
+}
@@ -0,0 +1,72 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import "net/http"
+
+// ClientSet holds a client for each of the operation groups of the service,
+// all sharing one configured ManagementClient: the same http.Client (hence
+// the same transport and its connections), authorizer and settings.
+//
+// Each field holds its own copy of the ManagementClient, so configure the
+// ManagementClient before passing it to NewClientSetWithClient; changing a
+// field of one of the clients afterward does not affect the others.
+type ClientSet struct {
+	Agreements                   AgreementsClient
+	Certificates                 CertificatesClient
+	IntegrationAccounts          IntegrationAccountsClient
+	Maps                         MapsClient
+	Partners                     PartnersClient
+	Schemas                      SchemasClient
+	Sessions                     SessionsClient
+	WorkflowRunActionRepetitions WorkflowRunActionRepetitionsClient
+	WorkflowRunActions           WorkflowRunActionsClient
+	WorkflowRuns                 WorkflowRunsClient
+	Workflows                    WorkflowsClient
+	WorkflowTriggerHistories     WorkflowTriggerHistoriesClient
+	WorkflowTriggers             WorkflowTriggersClient
+	WorkflowVersions             WorkflowVersionsClient
+}
+
+// NewClientSet creates an instance of the ClientSet using the default
+// ManagementClient, see New. Use NewClientSetWithClient to share an
+// Authorizer, or any other setting, among the clients.
+func NewClientSet(subscriptionID string) ClientSet {
+	return NewClientSetWithClient(New(subscriptionID))
+}
+
+// NewClientSetWithClient creates an instance of the ClientSet whose clients
+// all use client. If client has no Sender, a single http.Client is created
+// for all of them to share, rather than one per request.
+func NewClientSetWithClient(client ManagementClient) ClientSet {
+	if client.Sender == nil {
+		client.Sender = &http.Client{}
+	}
+	return ClientSet{
+		Agreements:                   AgreementsClient{client},
+		Certificates:                 CertificatesClient{client},
+		IntegrationAccounts:          IntegrationAccountsClient{client},
+		Maps:                         MapsClient{client},
+		Partners:                     PartnersClient{client},
+		Schemas:                      SchemasClient{client},
+		Sessions:                     SessionsClient{client},
+		WorkflowRunActionRepetitions: WorkflowRunActionRepetitionsClient{client},
+		WorkflowRunActions:           WorkflowRunActionsClient{client},
+		WorkflowRuns:                 WorkflowRunsClient{client},
+		Workflows:                    WorkflowsClient{client},
+		WorkflowTriggerHistories:     WorkflowTriggerHistoriesClient{client},
+		WorkflowTriggers:             WorkflowTriggersClient{client},
+		WorkflowVersions:             WorkflowVersionsClient{client},
+	}
+}
@@ -0,0 +1,41 @@
+package logic
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+func TestNewClientSetWithClient(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if got := r.Header.Get("X-Shared"); got != "set" {
+			t.Errorf("%s: got X-Shared %q, want the shared header", r.URL.Path, got)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := NewWithBaseURI(srv.URL, "subscription")
+	client.RequestInspector = autorest.WithHeader("X-Shared", "set")
+	set := NewClientSetWithClient(client)
+	if set.Workflows.Sender == nil || set.Workflows.Sender != set.WorkflowRuns.Sender {
+		t.Fatal("the clients do not share a Sender")
+	}
+
+	if _, err := set.Workflows.Get("group", "workflow"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := set.WorkflowRuns.Get("group", "workflow", "run"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := set.WorkflowTriggers.Get("group", "workflow", "trigger"); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Fatalf("got %d requests, want 3", requests)
+	}
+}
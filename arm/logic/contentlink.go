@@ -0,0 +1,126 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+// ErrContentHashMismatch is returned by FetchContentLink when the content
+// does not match the hash of its link.
+var ErrContentHashMismatch = errors.New("logic: content does not match its content link hash")
+
+// FetchContentLink downloads the content a content link, such as the
+// InputsLink and OutputsLink of the workflow run actions, refers to. The URI
+// of the link is pre-authorized, so the request is sent with the Sender of
+// the client but without its Authorizer. The size and the hash of the
+// content are verified against those of the link when it carries them;
+// hashes by other algorithms than MD5, SHA1 and SHA256 are not verified.
+// The errors do not include the query of the URI, which holds its
+// signature.
+func (client ManagementClient) FetchContentLink(ctx context.Context, link ContentLink) ([]byte, error) {
+	uri := to.String(link.URI)
+	if uri == "" {
+		return nil, errors.New("logic: content link has no URI")
+	}
+	req, err := http.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("logic: invalid content link URI: %w", redactURLError(err))
+	}
+	req = req.WithContext(ctx)
+
+	sender := client.Sender
+	if sender == nil {
+		sender = http.DefaultClient
+	}
+	resp, err := autorest.SendWithSender(sender, req)
+	if err != nil {
+		return nil, fmt.Errorf("logic: fetching content link %s: %w", describeRequest(req), redactURLError(err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("logic: fetching content link %s: unexpected status %d", describeRequest(req), resp.StatusCode)
+	}
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("logic: reading content link %s: %w", describeRequest(req), redactURLError(err))
+	}
+
+	if link.ContentSize != nil && int64(len(content)) != *link.ContentSize {
+		return nil, fmt.Errorf("logic: content link %s: got %d bytes, want %d", describeRequest(req), len(content), *link.ContentSize)
+	}
+	if err := verifyContentHash(content, link.ContentHash); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, describeRequest(req))
+	}
+	return content, nil
+}
+
+// verifyContentHash checks content against the base64 encoded hash, if
+// given by a supported algorithm.
+func verifyContentHash(content []byte, contentHash *ContentHash) error {
+	if contentHash == nil || to.String(contentHash.Value) == "" {
+		return nil
+	}
+	var h hash.Hash
+	switch strings.ToLower(to.String(contentHash.Algorithm)) {
+	case "md5":
+		h = md5.New()
+	case "sha1":
+		h = sha1.New()
+	case "sha256":
+		h = sha256.New()
+	default:
+		return nil
+	}
+	want, err := base64.StdEncoding.DecodeString(to.String(contentHash.Value))
+	if err != nil {
+		return fmt.Errorf("logic: invalid content link hash: %v", err)
+	}
+	h.Write(content)
+	if !bytes.Equal(h.Sum(nil), want) {
+		return ErrContentHashMismatch
+	}
+	return nil
+}
+
+// redactURLError strips the query, i.e. the signature of a content link,
+// off the URL err reports, if any.
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+	if i := strings.IndexByte(urlErr.URL, '?'); i >= 0 {
+		redacted := *urlErr
+		redacted.URL = urlErr.URL[:i]
+		return &redacted
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package logic
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+// staticToken is an adal.OAuthTokenProvider of a fixed token.
+type staticToken string
+
+func (t staticToken) OAuthToken() string { return string(t) }
+
+func TestFetchContentLink(t *testing.T) {
+	const content = `{"body":"output"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Error("the content link request carries the credentials of the client")
+		}
+		if r.URL.Query().Get("sig") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, content)
+	}))
+	defer srv.Close()
+
+	client := NewWithBaseURI(srv.URL, "subscription")
+	client.Authorizer = autorest.NewBearerAuthorizer(staticToken("token"))
+	sum := md5.Sum([]byte(content))
+	link := ContentLink{
+		URI:         to.StringPtr(srv.URL + "/content?sig=secret"),
+		ContentSize: to.Int64Ptr(int64(len(content))),
+		ContentHash: &ContentHash{Algorithm: to.StringPtr("md5"), Value: to.StringPtr(base64.StdEncoding.EncodeToString(sum[:]))},
+	}
+	got, err := client.FetchContentLink(context.Background(), link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != content {
+		t.Fatalf("got content %q, want %q", got, content)
+	}
+
+	link.ContentHash.Value = to.StringPtr(base64.StdEncoding.EncodeToString(make([]byte, md5.Size)))
+	if _, err := client.FetchContentLink(context.Background(), link); !errors.Is(err, ErrContentHashMismatch) {
+		t.Fatalf("FetchContentLink() with a wrong hash = %v, want %v", err, ErrContentHashMismatch)
+	}
+
+	link.URI = to.StringPtr(srv.URL + "/content?sig=wrong")
+	_, err = client.FetchContentLink(context.Background(), link)
+	if err == nil || !strings.Contains(err.Error(), "403") || strings.Contains(err.Error(), "sig=") {
+		t.Fatalf("FetchContentLink() with a wrong signature = %v, want a 403 error without the signature", err)
+	}
+}
@@ -0,0 +1,53 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// CanonicalDefinitionJSON marshals a workflow definition into its canonical
+// JSON form: object keys are sorted at every level of nesting, insignificant
+// whitespace is dropped and HTML characters, which are common in workflow
+// expressions, are not escaped. Equivalent definitions thus always serialize
+// to identical bytes, which makes them suitable for diffing or change
+// detection.
+//
+// Values nested in def may be of any type encoding/json marshals, including
+// structs and json.RawMessage; they are normalized the same way.
+func CanonicalDefinitionJSON(def map[string]interface{}) ([]byte, error) {
+	raw, err := json.Marshal(def)
+	if err != nil {
+		return nil, err
+	}
+
+	// Round-trip through generic values so that struct fields and raw
+	// messages get sorted too. UseNumber keeps numbers verbatim.
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,78 @@
+package logic
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCanonicalDefinitionJSON(t *testing.T) {
+	testCases := []struct {
+		def  map[string]interface{}
+		want string
+	}{
+		{
+			map[string]interface{}{},
+			`{}`,
+		},
+		{
+			map[string]interface{}{
+				"triggers": map[string]interface{}{
+					"manual": map[string]interface{}{"type": "Request", "kind": "Http"},
+				},
+				"actions": map[string]interface{}{},
+				"$schema": "https://schema.management.azure.com/schema.json",
+			},
+			`{"$schema":"https://schema.management.azure.com/schema.json","actions":{},"triggers":{"manual":{"kind":"Http","type":"Request"}}}`,
+		},
+		{
+			map[string]interface{}{
+				"runAfter": []interface{}{
+					map[string]interface{}{"z": 1, "a": []interface{}{2.5, "x"}},
+					"Succeeded",
+				},
+				"expression": "@and(equals(1, 1), less(1, 2)) && <b>",
+			},
+			`{"expression":"@and(equals(1, 1), less(1, 2)) && <b>","runAfter":[{"a":[2.5,"x"],"z":1},"Succeeded"]}`,
+		},
+		{
+			map[string]interface{}{
+				"inputs": json.RawMessage(`{ "uri": "https://example.com", "method": "GET" }`),
+				"limit":  struct{ Count, Timeout int }{10, 20},
+			},
+			`{"inputs":{"method":"GET","uri":"https://example.com"},"limit":{"Count":10,"Timeout":20}}`,
+		},
+	}
+
+	for i, testCase := range testCases {
+		got, err := CanonicalDefinitionJSON(testCase.def)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if string(got) != testCase.want {
+			t.Errorf("Test %d: got %s, want %s", i+1, got, testCase.want)
+		}
+	}
+}
+
+func TestCanonicalDefinitionJSONRoundTrip(t *testing.T) {
+	const def = `{"actions":{"b":{"type":"Http","inputs":{"uri":"x"}},"a":{"type":"Response"}},"outputs":{}}`
+
+	var first, second map[string]interface{}
+	if err := json.Unmarshal([]byte(def), &first); err != nil {
+		t.Fatal(err)
+	}
+	canonical, err := CanonicalDefinitionJSON(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(canonical, &second); err != nil {
+		t.Fatal(err)
+	}
+	again, err := CanonicalDefinitionJSON(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(canonical) != string(again) {
+		t.Fatalf("got %s after round-trip, want %s", again, canonical)
+	}
+}
@@ -0,0 +1,39 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+// EnvironmentBaseURI returns the Azure Resource Manager endpoint of env in the
+// form expected by the NewWithBaseURI constructors. If env has no Resource
+// Manager endpoint, DefaultBaseURI is returned.
+func EnvironmentBaseURI(env azure.Environment) string {
+	if env.ResourceManagerEndpoint == "" {
+		return DefaultBaseURI
+	}
+	return strings.TrimSuffix(env.ResourceManagerEndpoint, "/")
+}
+
+// NewWorkflowRunsClientForEnvironment creates an instance of the
+// WorkflowRunsClient client targeting the Resource Manager endpoint of env,
+// e.g. azure.USGovernmentCloud or an environment returned by
+// azure.EnvironmentFromName.
+func NewWorkflowRunsClientForEnvironment(subscriptionID string, env azure.Environment) WorkflowRunsClient {
+	return NewWorkflowRunsClientWithBaseURI(EnvironmentBaseURI(env), subscriptionID)
+}
@@ -0,0 +1,31 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+func TestEnvironmentBaseURI(t *testing.T) {
+	testCases := []struct {
+		env  azure.Environment
+		want string
+	}{
+		{azure.PublicCloud, "https://management.azure.com"},
+		{azure.USGovernmentCloud, "https://management.usgovcloudapi.net"},
+		{azure.ChinaCloud, "https://management.chinacloudapi.cn"},
+		{azure.GermanCloud, "https://management.microsoftazure.de"},
+		{azure.Environment{ResourceManagerEndpoint: "https://management.example.com/"}, "https://management.example.com"},
+		{azure.Environment{ResourceManagerEndpoint: "https://management.example.com"}, "https://management.example.com"},
+		{azure.Environment{Name: "Empty"}, DefaultBaseURI},
+	}
+	for _, testCase := range testCases {
+		if got := EnvironmentBaseURI(testCase.env); got != testCase.want {
+			t.Errorf("EnvironmentBaseURI(%q)=%q, want %q", testCase.env.ResourceManagerEndpoint, got, testCase.want)
+		}
+	}
+
+	if client := NewWorkflowRunsClientForEnvironment("subscription", azure.ChinaCloud); client.BaseURI != "https://management.chinacloudapi.cn" {
+		t.Fatalf("got BaseURI %q, want that of the China cloud", client.BaseURI)
+	}
+}
@@ -0,0 +1,180 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+// LogicError is the error returned by the service for a failed request, as
+// decoded from the ARM error body. The clients wrap it into an
+// autorest.DetailedError, use UnwrapError to get at it:
+//
+//	var logicErr *logic.LogicError
+//	if errors.As(logic.UnwrapError(err), &logicErr) && logicErr.Code == "WorkflowNotFound" {
+//		// ...
+//	}
+type LogicError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// RequestID is the x-ms-request-id of the response, if any.
+	RequestID string
+	// CorrelationRequestID is the CorrelationRequestIDHeader of the
+	// response, if any.
+	CorrelationRequestID string
+
+	Code    string        `json:"code"`
+	Message string        `json:"message"`
+	Target  string        `json:"target,omitempty"`
+	Details []ErrorDetail `json:"details,omitempty"`
+
+	// SupportedVersions lists the API versions the service reported it
+	// supports when rejecting the requested one, e.g. with an
+	// InvalidApiVersionParameter or NoRegisteredProviderFound error; see
+	// NewWithAPIVersion. It is nil if the message does not list them.
+	SupportedVersions []string `json:"-"`
+
+	requestError *azure.RequestError
+}
+
+// ErrorDetail is an additional error reported along with a LogicError.
+type ErrorDetail struct {
+	Code    string        `json:"code"`
+	Message string        `json:"message"`
+	Target  string        `json:"target,omitempty"`
+	Details []ErrorDetail `json:"details,omitempty"`
+}
+
+func (e *LogicError) Error() string {
+	msg := fmt.Sprintf("logic: %s (status %d): %s", e.Code, e.StatusCode, e.Message)
+	for _, detail := range e.Details {
+		msg += fmt.Sprintf("; %s: %s", detail.Code, detail.Message)
+	}
+	return msg
+}
+
+// Unwrap returns the *azure.RequestError the error was decoded along with,
+// for the callers which inspect that one.
+func (e *LogicError) Unwrap() error {
+	if e.requestError == nil {
+		return nil
+	}
+	return e.requestError
+}
+
+// withErrorUnlessStatusCode works like azure.WithErrorUnlessStatusCode, but
+// returns a *LogicError for the responses failing with an ARM error body.
+func withErrorUnlessStatusCode(codes ...int) autorest.RespondDecorator {
+	return func(r autorest.Responder) autorest.Responder {
+		return autorest.ResponderFunc(func(resp *http.Response) error {
+			err := azure.WithErrorUnlessStatusCode(codes...)(r).Respond(resp)
+			requestErr, ok := err.(*azure.RequestError)
+			if !ok {
+				return err
+			}
+
+			// azure.WithErrorUnlessStatusCode leaves a copy of the body in place.
+			b, readErr := ioutil.ReadAll(resp.Body)
+			resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+			var body struct {
+				Error *LogicError `json:"error"`
+			}
+			if readErr != nil || json.Unmarshal(b, &body) != nil || body.Error == nil {
+				return err
+			}
+			body.Error.StatusCode = resp.StatusCode
+			body.Error.RequestID = requestErr.RequestID
+			body.Error.CorrelationRequestID = resp.Header.Get(CorrelationRequestIDHeader)
+			body.Error.requestError = requestErr
+			body.Error.SupportedVersions = supportedVersions(body.Error.Message)
+			return body.Error
+		})
+	}
+}
+
+// supportedVersionsPattern matches the versions Resource Manager lists in
+// InvalidApiVersionParameter ("The supported versions are '...'") and
+// NoRegisteredProviderFound ("The supported api-versions are '...'")
+// messages. Only the first quoted list is captured: the latter messages go
+// on to list the supported locations the same way. It mirrors the pattern of
+// the management package, which this package does not depend on.
+var supportedVersionsPattern = regexp.MustCompile(`(?i)supported (?:api-)?versions are '([^']*)'`)
+
+// supportedVersions returns the comma separated versions of the message,
+// trimmed, for LogicError.SupportedVersions.
+func supportedVersions(message string) []string {
+	m := supportedVersionsPattern.FindStringSubmatch(message)
+	if m == nil {
+		return nil
+	}
+	var versions []string
+	for _, v := range strings.Split(m[1], ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+// UnwrapError returns the error which caused a failure reported by the
+// clients of this package. The clients wrap send failures into an
+// autorest.DetailedError, which does not support errors.Unwrap; UnwrapError
+// strips these layers off, so that the result can be inspected with
+// errors.Is and errors.As, for instance to tell timeouts, refused
+// connections and DNS failures apart:
+//
+//	_, err := client.Get(resourceGroupName, workflowName)
+//	var netErr net.Error
+//	if errors.As(logic.UnwrapError(err), &netErr) && netErr.Timeout() {
+//		// retry later
+//	}
+//
+// Errors which do not wrap another error are returned unchanged.
+func UnwrapError(err error) error {
+	for {
+		switch e := err.(type) {
+		case autorest.DetailedError:
+			if e.Original == nil {
+				return err
+			}
+			err = e.Original
+		case *autorest.DetailedError:
+			if e == nil || e.Original == nil {
+				return err
+			}
+			err = e.Original
+		default:
+			return err
+		}
+	}
+}
+
+// describeRequest returns the method and path of req, for the messages of
+// the errors reported by the clients.
+func describeRequest(req *http.Request) string {
+	if req == nil || req.URL == nil {
+		return "<nil request>"
+	}
+	return req.Method + " " + req.URL.Path
+}
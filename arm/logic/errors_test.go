@@ -0,0 +1,121 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+func TestUnwrapErrorNetError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+	client.RetryDuration = time.Millisecond
+	_, err := client.Get("group", "workflow", "run")
+	if _, ok := err.(autorest.DetailedError); !ok {
+		t.Fatalf("got error %v (%T), want autorest.DetailedError", err, err)
+	}
+	if want := "GET /subscriptions/subscription/resourceGroups/group/providers/Microsoft.Logic/workflows/workflow/runs/run"; !strings.Contains(err.Error(), want) {
+		t.Fatalf("got error %q, want it to mention %q", err, want)
+	}
+	var opErr *net.OpError
+	if !errors.As(UnwrapError(err), &opErr) {
+		t.Fatalf("got error %v, want it to wrap a *net.OpError", UnwrapError(err))
+	}
+}
+
+func TestUnwrapErrorDeadline(t *testing.T) {
+	original := autorest.NewErrorWithError(context.DeadlineExceeded, "logic.WorkflowRunsClient", "Get", nil, "Failure sending request")
+	if err := UnwrapError(original); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("UnwrapError()=%v, want %v", err, context.DeadlineExceeded)
+	}
+
+	detailed := autorest.NewError("logic.WorkflowRunsClient", "Get", "lost after %v", time.Second)
+	if err, ok := UnwrapError(detailed).(autorest.DetailedError); !ok || err.Message != detailed.Message {
+		t.Fatalf("UnwrapError()=%v, want the error unchanged", err)
+	}
+}
+
+func TestLogicErrorSupportedVersions(t *testing.T) {
+	body, err := os.ReadFile("testdata/unsupported-api-version.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(body)
+	}))
+	defer srv.Close()
+
+	client, err := NewWithAPIVersion(srv.URL, "subscription", "2099-01-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = WorkflowsClient{client}.Get("group", "workflow")
+	var logicErr *LogicError
+	if !errors.As(UnwrapError(err), &logicErr) {
+		t.Fatalf("got error %v, want it to wrap a *LogicError", err)
+	}
+	want := []string{"2015-02-01-preview", "2015-08-01-preview", "2016-06-01"}
+	if !reflect.DeepEqual(logicErr.SupportedVersions, want) {
+		t.Fatalf("got supported versions %q, want %q", logicErr.SupportedVersions, want)
+	}
+}
+
+func TestSupportedVersions(t *testing.T) {
+	testCases := []struct {
+		message string
+		want    []string
+	}{
+		{"The api-version '2099-01-01' is invalid. The supported versions are '2015-02-01-preview,2016-06-01'.", []string{"2015-02-01-preview", "2016-06-01"}},
+		{"No registered resource provider found for location 'westus' and API version '2099-01-01' for type 'workflows'. The supported api-versions are '2016-06-01'. The supported locations are 'westus, eastus'.", []string{"2016-06-01"}},
+		{"THE SUPPORTED API-VERSIONS ARE ' 2015-08-01-preview , , 2016-06-01 '", []string{"2015-08-01-preview", "2016-06-01"}},
+		{"The supported versions are ''.", nil},
+		{"The workflow 'workflow' could not be found.", nil},
+		{"", nil},
+	}
+	for _, testCase := range testCases {
+		if got := supportedVersions(testCase.message); !reflect.DeepEqual(got, testCase.want) {
+			t.Errorf("supportedVersions(%q)=%q, want %q", testCase.message, got, testCase.want)
+		}
+	}
+}
+
+func TestLogicError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ms-request-id", "request")
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"error":{"code":"InvalidTemplate","message":"bad definition",`+
+			`"details":[{"code":"InvalidAction","message":"unknown action","target":"actions.a"}]}}`)
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowsClientWithBaseURI(srv.URL, "subscription")
+	_, err := client.Get("group", "workflow")
+	var logicErr *LogicError
+	if !errors.As(UnwrapError(err), &logicErr) {
+		t.Fatalf("got error %v (%T), want it to wrap a *LogicError", err, UnwrapError(err))
+	}
+	if logicErr.StatusCode != http.StatusBadRequest || logicErr.Code != "InvalidTemplate" || logicErr.RequestID != "request" {
+		t.Fatalf("got %+v, want a 400 InvalidTemplate error of request", logicErr)
+	}
+	if len(logicErr.Details) != 1 || logicErr.Details[0].Target != "actions.a" {
+		t.Fatalf("got details %+v, want the InvalidAction one", logicErr.Details)
+	}
+	var requestErr *azure.RequestError
+	if !errors.As(UnwrapError(err), &requestErr) {
+		t.Fatal("the *LogicError does not wrap the *azure.RequestError")
+	}
+}
@@ -0,0 +1,130 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// DefaultETagCacheSize is the number of resources an ETagCache created with
+// a non-positive size holds.
+const DefaultETagCacheSize = 256
+
+// ETagCache is a bounded, least recently used cache of resources keyed by
+// their URL, along with the ETag they were returned with. Set it as the
+// ETagCache of a client to make it revalidate the cached resources with
+// If-None-Match rather than download them again. It is safe for concurrent
+// use and may be shared by several clients.
+type ETagCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type etagEntry struct {
+	key   string
+	etag  string
+	value interface{}
+}
+
+// NewETagCache creates a cache holding at most size resources, or
+// DefaultETagCacheSize if size is not positive.
+func NewETagCache(size int) *ETagCache {
+	if size <= 0 {
+		size = DefaultETagCacheSize
+	}
+	return &ETagCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Len returns the number of resources in the cache.
+func (c *ETagCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *ETagCache) get(key string) (etag string, value interface{}, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return "", nil, false
+	}
+	c.order.MoveToFront(e)
+	entry := e.Value.(*etagEntry)
+	return entry.etag, entry.value, true
+}
+
+func (c *ETagCache) put(key, etag string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value = &etagEntry{key: key, etag: etag, value: value}
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&etagEntry{key: key, etag: etag, value: value})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*etagEntry).key)
+	}
+}
+
+// getCached sends the prepared Get request through the client's ETagCache.
+// On a 304 Not Modified, the cached run is returned with the Response of
+// the 304.
+func (client WorkflowRunsClient) getCached(req *http.Request) (result WorkflowRun, err error) {
+	key := req.URL.String()
+	etag, value, cached := client.ETagCache.get(key)
+	if cached {
+		if req.Header == nil {
+			req.Header = make(http.Header)
+		}
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := client.GetSender(req)
+	if err != nil {
+		result.Response = autorest.Response{Response: resp}
+		err = autorest.NewErrorWithError(err, "logic.WorkflowRunsClient", "Get", resp, "Failure sending request: %s", describeRequest(req))
+		return
+	}
+	if cached && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		result = value.(WorkflowRun)
+		result.Response = autorest.Response{Response: resp}
+		return
+	}
+
+	result, err = client.GetResponder(resp)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowRunsClient", "Get", resp, "Failure responding to request: %s", describeRequest(req))
+		return
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		client.ETagCache.put(key, etag, result)
+	}
+	return
+}
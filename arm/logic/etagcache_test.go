@@ -0,0 +1,49 @@
+package logic
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetETagCache(t *testing.T) {
+	var downloads, revalidations int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidations++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"name":"run1","properties":{"status":"Running"}}`)
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "sub")
+	client.ETagCache = NewETagCache(1)
+	for i := 0; i < 3; i++ {
+		run, err := client.Get("rg", "wf", "run1")
+		if err != nil {
+			t.Fatalf("Get() %d: %v", i, err)
+		}
+		if run.Name == nil || *run.Name != "run1" {
+			t.Fatalf("Get() %d: got run %+v, want run1", i, run)
+		}
+	}
+	if downloads != 1 || revalidations != 2 {
+		t.Fatalf("got %d downloads and %d revalidations, want 1 and 2", downloads, revalidations)
+	}
+
+	// A second run evicts the first from the single entry cache.
+	if _, err := client.Get("rg", "wf", "run2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Get("rg", "wf", "run1"); err != nil {
+		t.Fatal(err)
+	}
+	if downloads != 3 || client.ETagCache.Len() != 1 {
+		t.Fatalf("got %d downloads and %d cached runs, want 3 and 1", downloads, client.ETagCache.Len())
+	}
+}
@@ -0,0 +1,150 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// CorrelationRequestIDHeader carries the ID tying together the requests of a
+// multi-resource deployment, unlike the x-ms-request-id identifying a single
+// request. See ManagementClient.CorrelationRequestID.
+const CorrelationRequestIDHeader = "x-ms-correlation-request-id"
+
+// CorrelationRequestID returns the CorrelationRequestIDHeader of the response
+// a result was read from, e.g. of a WorkflowRun, or an empty string if the
+// response carried none. For a failed request, see
+// LogicError.CorrelationRequestID.
+func CorrelationRequestID(response autorest.Response) string {
+	if response.Response == nil {
+		return ""
+	}
+	return response.Header.Get(CorrelationRequestIDHeader)
+}
+
+// withHeaders returns a copy of the client whose requests carry the given
+// extra headers, e.g. to enable a preview feature for a single call; it
+// implements the WithHeaders methods of the clients by wrapping their
+// RequestInspector. The headers the client sets itself, such as Content-Type,
+// win on collision unless named in override; Authorization is always set by
+// the client's Authorizer. The headers are added before any RequestInspector
+// of the client runs.
+func (client ManagementClient) withHeaders(headers http.Header, override []string) ManagementClient {
+	extra := make(http.Header, len(headers))
+	for key, values := range headers {
+		extra[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+	}
+	overridable := make(map[string]bool, len(override))
+	for _, key := range override {
+		overridable[http.CanonicalHeaderKey(key)] = true
+	}
+
+	inspector := client.RequestInspector
+	client.RequestInspector = func(p autorest.Preparer) autorest.Preparer {
+		withExtra := autorest.PreparerFunc(func(r *http.Request) (*http.Request, error) {
+			r, err := p.Prepare(r)
+			if err != nil {
+				return r, err
+			}
+			if r.Header == nil {
+				r.Header = make(http.Header)
+			}
+			for key, values := range extra {
+				if _, ok := r.Header[key]; ok && !overridable[key] {
+					continue
+				}
+				r.Header[key] = values
+			}
+			return r, nil
+		})
+		if inspector != nil {
+			return inspector(withExtra)
+		}
+		return withExtra
+	}
+	return client
+}
+
+// WithHeaders returns a copy of the AgreementsClient sending the given extra headers, which replace its own only if named in override.
+func (client AgreementsClient) WithHeaders(headers http.Header, override ...string) AgreementsClient {
+	return AgreementsClient{client.ManagementClient.withHeaders(headers, override)}
+}
+
+// WithHeaders returns a copy of the CertificatesClient sending the given extra headers, which replace its own only if named in override.
+func (client CertificatesClient) WithHeaders(headers http.Header, override ...string) CertificatesClient {
+	return CertificatesClient{client.ManagementClient.withHeaders(headers, override)}
+}
+
+// WithHeaders returns a copy of the IntegrationAccountsClient sending the given extra headers, which replace its own only if named in override.
+func (client IntegrationAccountsClient) WithHeaders(headers http.Header, override ...string) IntegrationAccountsClient {
+	return IntegrationAccountsClient{client.ManagementClient.withHeaders(headers, override)}
+}
+
+// WithHeaders returns a copy of the MapsClient sending the given extra headers, which replace its own only if named in override.
+func (client MapsClient) WithHeaders(headers http.Header, override ...string) MapsClient {
+	return MapsClient{client.ManagementClient.withHeaders(headers, override)}
+}
+
+// WithHeaders returns a copy of the PartnersClient sending the given extra headers, which replace its own only if named in override.
+func (client PartnersClient) WithHeaders(headers http.Header, override ...string) PartnersClient {
+	return PartnersClient{client.ManagementClient.withHeaders(headers, override)}
+}
+
+// WithHeaders returns a copy of the SchemasClient sending the given extra headers, which replace its own only if named in override.
+func (client SchemasClient) WithHeaders(headers http.Header, override ...string) SchemasClient {
+	return SchemasClient{client.ManagementClient.withHeaders(headers, override)}
+}
+
+// WithHeaders returns a copy of the SessionsClient sending the given extra headers, which replace its own only if named in override.
+func (client SessionsClient) WithHeaders(headers http.Header, override ...string) SessionsClient {
+	return SessionsClient{client.ManagementClient.withHeaders(headers, override)}
+}
+
+// WithHeaders returns a copy of the WorkflowRunActionsClient sending the given extra headers, which replace its own only if named in override.
+func (client WorkflowRunActionsClient) WithHeaders(headers http.Header, override ...string) WorkflowRunActionsClient {
+	return WorkflowRunActionsClient{client.ManagementClient.withHeaders(headers, override)}
+}
+
+// WithHeaders returns a copy of the WorkflowRunActionRepetitionsClient sending the given extra headers, which replace its own only if named in override.
+func (client WorkflowRunActionRepetitionsClient) WithHeaders(headers http.Header, override ...string) WorkflowRunActionRepetitionsClient {
+	return WorkflowRunActionRepetitionsClient{client.ManagementClient.withHeaders(headers, override)}
+}
+
+// WithHeaders returns a copy of the WorkflowRunsClient sending the given extra headers, which replace its own only if named in override.
+func (client WorkflowRunsClient) WithHeaders(headers http.Header, override ...string) WorkflowRunsClient {
+	return WorkflowRunsClient{client.ManagementClient.withHeaders(headers, override)}
+}
+
+// WithHeaders returns a copy of the WorkflowsClient sending the given extra headers, which replace its own only if named in override.
+func (client WorkflowsClient) WithHeaders(headers http.Header, override ...string) WorkflowsClient {
+	return WorkflowsClient{client.ManagementClient.withHeaders(headers, override)}
+}
+
+// WithHeaders returns a copy of the WorkflowTriggerHistoriesClient sending the given extra headers, which replace its own only if named in override.
+func (client WorkflowTriggerHistoriesClient) WithHeaders(headers http.Header, override ...string) WorkflowTriggerHistoriesClient {
+	return WorkflowTriggerHistoriesClient{client.ManagementClient.withHeaders(headers, override)}
+}
+
+// WithHeaders returns a copy of the WorkflowTriggersClient sending the given extra headers, which replace its own only if named in override.
+func (client WorkflowTriggersClient) WithHeaders(headers http.Header, override ...string) WorkflowTriggersClient {
+	return WorkflowTriggersClient{client.ManagementClient.withHeaders(headers, override)}
+}
+
+// WithHeaders returns a copy of the WorkflowVersionsClient sending the given extra headers, which replace its own only if named in override.
+func (client WorkflowVersionsClient) WithHeaders(headers http.Header, override ...string) WorkflowVersionsClient {
+	return WorkflowVersionsClient{client.ManagementClient.withHeaders(headers, override)}
+}
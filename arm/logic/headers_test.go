@@ -0,0 +1,100 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+func TestWithHeaders(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		fmt.Fprint(w, `{"name":"run"}`)
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+	headers := http.Header{
+		"x-ms-preview-feature": {"on"},
+		"User-Agent":           {"override"},
+		"X-Custom":             {"a", "b"},
+	}
+	if _, err := client.WithHeaders(headers).Get("group", "workflow", "run"); err != nil {
+		t.Fatal(err)
+	}
+	if v := got.Get("X-Ms-Preview-Feature"); v != "on" {
+		t.Fatalf("got preview header %q, want on", v)
+	}
+	if v := got["X-Custom"]; len(v) != 2 {
+		t.Fatalf("got X-Custom %q, want both values", v)
+	}
+	if v := got.Get("User-Agent"); v == "override" {
+		t.Fatal("the extra headers replaced the User-Agent set by the client")
+	}
+
+	if _, err := client.WithHeaders(headers, "user-agent").Get("group", "workflow", "run"); err != nil {
+		t.Fatal(err)
+	}
+	if v := got.Get("User-Agent"); v != "override" {
+		t.Fatalf("got User-Agent %q, want the explicitly allowed override", v)
+	}
+
+	// The original client is unaffected.
+	if _, err := client.Get("group", "workflow", "run"); err != nil {
+		t.Fatal(err)
+	}
+	if v := got.Get("X-Ms-Preview-Feature"); v != "" {
+		t.Fatalf("got preview header %q on a plain call, want none", v)
+	}
+}
+
+func TestCorrelationRequestID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(CorrelationRequestIDHeader)
+		if id == "" {
+			id = "assigned"
+		}
+		w.Header().Set(CorrelationRequestIDHeader, id)
+		if r.Method == http.MethodDelete {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"error":{"code":"WorkflowRunInProgress","message":"busy"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"name":"run"}`)
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+	run, err := client.Get("group", "workflow", "run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := CorrelationRequestID(run.Response); got != "assigned" {
+		t.Fatalf("got correlation request ID %q, want the assigned one", got)
+	}
+
+	workflows := NewWorkflowsClientWithBaseURI(srv.URL, "subscription")
+	workflows.CorrelationRequestID = "batch"
+	workflow, err := workflows.Get("group", "workflow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := CorrelationRequestID(workflow.Response); got != "batch" {
+		t.Fatalf("got correlation request ID %q, want %q", got, "batch")
+	}
+	_, err = workflows.Delete("group", "workflow")
+	var logicErr *LogicError
+	if !errors.As(UnwrapError(err), &logicErr) || logicErr.CorrelationRequestID != "batch" {
+		t.Fatalf("Delete()=%v, want a LogicError with the correlation request ID %q", err, "batch")
+	}
+
+	if got := CorrelationRequestID(autorest.Response{}); got != "" {
+		t.Fatalf("got correlation request ID %q without a response", got)
+	}
+}
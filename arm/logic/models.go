@@ -1465,6 +1465,13 @@ type RegenerateActionParameter struct {
 	KeyType KeyType `json:"keyType,omitempty"`
 }
 
+// RepetitionIndex is the index of an iteration of a loop, identifying a
+// workflow run action repetition.
+type RepetitionIndex struct {
+	ScopeName *string `json:"scopeName,omitempty"`
+	ItemIndex *int32  `json:"itemIndex,omitempty"`
+}
+
 // Resource is the base resource type.
 type Resource struct {
 	ID       *string             `json:"id,omitempty"`
@@ -1616,6 +1623,40 @@ func (client WorkflowRunActionListResult) WorkflowRunActionListResultPreparer()
 		autorest.WithBaseURL(to.String(client.NextLink)))
 }
 
+// WorkflowRunActionRepetition is a repetition of a workflow run action, i.e.
+// one iteration of the foreach or until loop the action is part of.
+type WorkflowRunActionRepetition struct {
+	autorest.Response                      `json:"-"`
+	ID                                     *string `json:"id,omitempty"`
+	*WorkflowRunActionRepetitionProperties `json:"properties,omitempty"`
+	Name                                   *string `json:"name,omitempty"`
+	Type                                   *string `json:"type,omitempty"`
+}
+
+// WorkflowRunActionRepetitionListResult is the list of workflow run action
+// repetitions.
+type WorkflowRunActionRepetitionListResult struct {
+	autorest.Response `json:"-"`
+	Value             *[]WorkflowRunActionRepetition `json:"value,omitempty"`
+}
+
+// WorkflowRunActionRepetitionProperties is the workflow run action
+// repetition properties.
+type WorkflowRunActionRepetitionProperties struct {
+	StartTime         *date.Time              `json:"startTime,omitempty"`
+	EndTime           *date.Time              `json:"endTime,omitempty"`
+	Status            WorkflowStatus          `json:"status,omitempty"`
+	Code              *string                 `json:"code,omitempty"`
+	Error             *map[string]interface{} `json:"error,omitempty"`
+	TrackingID        *string                 `json:"trackingId,omitempty"`
+	Correlation       *Correlation            `json:"correlation,omitempty"`
+	InputsLink        *ContentLink            `json:"inputsLink,omitempty"`
+	OutputsLink       *ContentLink            `json:"outputsLink,omitempty"`
+	TrackedProperties *map[string]interface{} `json:"trackedProperties,omitempty"`
+	RetryHistory      *[]RetryHistory         `json:"retryHistory,omitempty"`
+	RepetitionIndexes *[]RepetitionIndex      `json:"repetitionIndexes,omitempty"`
+}
+
 // WorkflowRunActionProperties is the workflow run action properties.
 type WorkflowRunActionProperties struct {
 	StartTime         *date.Time              `json:"startTime,omitempty"`
@@ -0,0 +1,174 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidFilter is returned, wrapped with the details of the mistake, for
+// a $filter expression which ValidateFilter rejects.
+var ErrInvalidFilter = errors.New("logic: invalid filter")
+
+// filterOperators are the logical and arithmetic operators of OData.
+var filterOperators = map[string]bool{
+	"eq": true, "ne": true, "gt": true, "ge": true, "lt": true, "le": true,
+	"and": true, "or": true, "has": true, "in": true,
+	"add": true, "sub": true, "mul": true, "div": true, "mod": true,
+}
+
+// ValidateFilter checks a $filter expression for the common mistakes which
+// the service would reject with 400 Bad Request: unbalanced quotes or
+// parentheses, operators in place of their operands or missing ones, and
+// unknown operators, such as "==", "EQ" or "equals" instead of "eq". It is
+// not a full OData parser, so an expression it accepts may still be rejected
+// by the service. An empty filter is valid.
+//
+// The list requests taking a filter, such as WorkflowRunsClient.List,
+// validate it before sending the request.
+func ValidateFilter(filter string) error {
+	var (
+		calls         []bool // whether each open parenthesis starts arguments
+		expectOperand = true
+		prev          string // the previous token
+	)
+	invalid := func(offset int, format string, args ...interface{}) error {
+		return fmt.Errorf("%w %q: %s at offset %d", ErrInvalidFilter, filter, fmt.Sprintf(format, args...), offset)
+	}
+
+	for i := 0; i < len(filter); {
+		c := filter[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+			continue
+		case c == '(':
+			// An opening parenthesis either groups an operand or starts
+			// the arguments of a function.
+			if !expectOperand && !isFilterWord(prev) {
+				return invalid(i, "missing operator before %q", "(")
+			}
+			calls = append(calls, !expectOperand)
+			expectOperand = true
+			prev = "("
+			i++
+			continue
+		case c == ')':
+			if len(calls) == 0 {
+				return invalid(i, "unbalanced %q", ")")
+			}
+			// Only a function may be called without arguments.
+			if expectOperand && (prev != "(" || !calls[len(calls)-1]) {
+				return invalid(i, "missing operand before %q", ")")
+			}
+			calls = calls[:len(calls)-1]
+			expectOperand = false
+			prev = ")"
+			i++
+			continue
+		case c == ',':
+			if len(calls) == 0 || expectOperand {
+				return invalid(i, "unexpected %q", ",")
+			}
+			expectOperand = true
+			prev = ","
+			i++
+			continue
+		case strings.IndexByte("=!<>&|", c) != -1:
+			j := i + 1
+			for j < len(filter) && strings.IndexByte("=!<>&|", filter[j]) != -1 {
+				j++
+			}
+			return invalid(i, "unknown operator %q, use eq, ne, gt, ge, lt, le, and, or or not", filter[i:j])
+		}
+
+		token, n, err := nextFilterOperand(filter[i:])
+		if err != nil {
+			return invalid(i, "%v", err)
+		}
+		switch {
+		case token == "not":
+			if !expectOperand {
+				return invalid(i, "unknown operator %q", token)
+			}
+		case filterOperators[token]:
+			if expectOperand {
+				return invalid(i, "operator %q is missing its left operand", token)
+			}
+			expectOperand = true
+		case !expectOperand && isFilterWord(token):
+			return invalid(i, "unknown operator %q", token)
+		case !expectOperand:
+			return invalid(i, "missing operator before %s", token)
+		default:
+			expectOperand = false
+		}
+		prev = token
+		i += n
+	}
+
+	switch {
+	case len(calls) != 0:
+		return invalid(len(filter), "unbalanced %q", "(")
+	case expectOperand && prev != "":
+		return invalid(len(filter), "operator %q is missing its right operand", prev)
+	}
+	return nil
+}
+
+// nextFilterOperand returns the word or literal s starts with, along with
+// its length. A quoted string may follow a word immediately, as in the typed
+// literals such as datetime'2017-01-01T00:00:00Z'.
+func nextFilterOperand(s string) (string, int, error) {
+	n := 0
+	for n < len(s) && isFilterWordByte(s[n]) {
+		n++
+	}
+	if n < len(s) && s[n] == '\'' {
+		// A quote is escaped by doubling it.
+		for n++; ; n++ {
+			if n == len(s) {
+				return "", 0, errors.New("unterminated string literal")
+			}
+			if s[n] == '\'' {
+				if n+1 < len(s) && s[n+1] == '\'' {
+					n++
+					continue
+				}
+				n++
+				break
+			}
+		}
+		if n < len(s) && isFilterWordByte(s[n]) {
+			return "", 0, fmt.Errorf("missing operator after %s", s[:n])
+		}
+	}
+	if n == 0 {
+		return "", 0, fmt.Errorf("unexpected %q", s[0])
+	}
+	return s[:n], n, nil
+}
+
+// isFilterWord reports whether token is a property or function name, or a
+// number, rather than a string literal or punctuation.
+func isFilterWord(token string) bool {
+	return token != "" && isFilterWordByte(token[0]) && !strings.HasSuffix(token, "'")
+}
+
+func isFilterWordByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		strings.IndexByte("_./:+-", c) != -1
+}
@@ -0,0 +1,75 @@
+package logic
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidateFilter(t *testing.T) {
+	valid := []string{
+		"",
+		"status eq 'Failed'",
+		"status eq 'Failed' and startTime ge 2017-01-01T00:00:00Z",
+		"(status eq 'Failed' or status eq 'Cancelled') and not (name eq 'it''s')",
+		"startswith(name, 'order') and startTime gt datetime'2017-01-01T00:00:00Z'",
+		"status in ('Failed', 'Cancelled')",
+		"code eq -1 or ratio lt 0.5",
+		"startTime lt now()",
+	}
+	for _, filter := range valid {
+		if err := ValidateFilter(filter); err != nil {
+			t.Errorf("ValidateFilter(%q)=%v", filter, err)
+		}
+	}
+
+	invalid := []string{
+		"status eq 'Failed",
+		"status eq 'Failed''",
+		"(status eq 'Failed'",
+		"status eq 'Failed')",
+		"status == 'Failed'",
+		"status EQ 'Failed'",
+		"status equals 'Failed'",
+		"status 'Failed'",
+		"status eq",
+		"eq 'Failed'",
+		"status eq 'Failed' and",
+		"status eq 'Failed' and ()",
+		"status eq 'Failed' (name eq 'x')",
+		"status eq 'a',",
+		"not",
+		"status eq 'a'b",
+		"status eq $x",
+	}
+	for _, filter := range invalid {
+		err := ValidateFilter(filter)
+		if !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("ValidateFilter(%q)=%v, want %v", filter, err, ErrInvalidFilter)
+		}
+	}
+}
+
+func TestListInvalidFilter(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+	if _, err := client.List("group", "workflow", nil, "status = 'Failed'"); err == nil {
+		t.Fatal("List() with an invalid filter succeeded")
+	}
+	if _, err := client.ListWithQuery("group", "workflow", ODataQuery{Filter: "(status eq 'Failed'"}); err == nil {
+		t.Fatal("ListWithQuery() with an invalid filter succeeded")
+	}
+	if requests != 0 {
+		t.Fatalf("got %d requests, want none", requests)
+	}
+	if _, err := client.List("group", "workflow", nil, "status eq 'Failed'"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,119 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ResourceID holds the components of a logic resource ID. WorkflowName is
+// always set; at most one of RunName and TriggerName is set, for the IDs of
+// workflow runs and workflow triggers respectively.
+type ResourceID struct {
+	SubscriptionID    string
+	ResourceGroupName string
+	WorkflowName      string
+	RunName           string
+	TriggerName       string
+}
+
+// String returns the resource ID in the form used by Azure Resource Manager.
+func (id ResourceID) String() string {
+	s := WorkflowID(id.SubscriptionID, id.ResourceGroupName, id.WorkflowName)
+	switch {
+	case id.RunName != "":
+		s += "/runs/" + url.PathEscape(id.RunName)
+	case id.TriggerName != "":
+		s += "/triggers/" + url.PathEscape(id.TriggerName)
+	}
+	return s
+}
+
+// WorkflowID returns the resource ID of a workflow.
+func WorkflowID(subscriptionID string, resourceGroupName string, workflowName string) string {
+	return "/subscriptions/" + url.PathEscape(subscriptionID) +
+		"/resourceGroups/" + url.PathEscape(resourceGroupName) +
+		"/providers/Microsoft.Logic/workflows/" + url.PathEscape(workflowName)
+}
+
+// WorkflowRunID returns the resource ID of a workflow run.
+func WorkflowRunID(subscriptionID string, resourceGroupName string, workflowName string, runName string) string {
+	return ResourceID{
+		SubscriptionID:    subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		WorkflowName:      workflowName,
+		RunName:           runName,
+	}.String()
+}
+
+// WorkflowTriggerID returns the resource ID of a workflow trigger.
+func WorkflowTriggerID(subscriptionID string, resourceGroupName string, workflowName string, triggerName string) string {
+	return ResourceID{
+		SubscriptionID:    subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		WorkflowName:      workflowName,
+		TriggerName:       triggerName,
+	}.String()
+}
+
+// ParseResourceID splits the ID of a workflow, workflow run or workflow
+// trigger, as returned in the ID field of the service responses, into its
+// components. The segment names are matched case-insensitively, since the
+// service does not always preserve their case.
+func ParseResourceID(id string) (ResourceID, error) {
+	segments := strings.Split(strings.Trim(id, "/"), "/")
+	if len(segments)%2 != 0 {
+		return ResourceID{}, fmt.Errorf("logic: invalid resource ID %q", id)
+	}
+
+	want := []string{"subscriptions", "resourceGroups", "providers", "workflows"}
+	if len(segments) < 2*len(want) {
+		return ResourceID{}, fmt.Errorf("logic: invalid resource ID %q", id)
+	}
+
+	values := make([]string, len(segments)/2)
+	for i := range values {
+		key := segments[2*i]
+		if i < len(want) && !strings.EqualFold(key, want[i]) {
+			return ResourceID{}, fmt.Errorf("logic: invalid resource ID %q: expected %q segment, got %q", id, want[i], key)
+		}
+		value, err := url.PathUnescape(segments[2*i+1])
+		if err != nil || value == "" {
+			return ResourceID{}, fmt.Errorf("logic: invalid resource ID %q: bad %q segment", id, key)
+		}
+		values[i] = value
+	}
+	if !strings.EqualFold(values[2], "Microsoft.Logic") {
+		return ResourceID{}, fmt.Errorf("logic: invalid resource ID %q: not a Microsoft.Logic resource", id)
+	}
+
+	r := ResourceID{
+		SubscriptionID:    values[0],
+		ResourceGroupName: values[1],
+		WorkflowName:      values[3],
+	}
+	switch {
+	case len(values) == 4:
+	case len(values) == 5 && strings.EqualFold(segments[8], "runs"):
+		r.RunName = values[4]
+	case len(values) == 5 && strings.EqualFold(segments[8], "triggers"):
+		r.TriggerName = values[4]
+	default:
+		return ResourceID{}, fmt.Errorf("logic: unsupported resource ID %q", id)
+	}
+	return r, nil
+}
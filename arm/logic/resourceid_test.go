@@ -0,0 +1,54 @@
+package logic
+
+import "testing"
+
+func TestResourceIDRoundTrip(t *testing.T) {
+	testCases := []struct {
+		id   string
+		want ResourceID
+	}{
+		{
+			WorkflowID("sub", "group", "flow"),
+			ResourceID{SubscriptionID: "sub", ResourceGroupName: "group", WorkflowName: "flow"},
+		},
+		{
+			WorkflowRunID("sub", "my group", "flow", "08586"),
+			ResourceID{SubscriptionID: "sub", ResourceGroupName: "my group", WorkflowName: "flow", RunName: "08586"},
+		},
+		{
+			WorkflowTriggerID("sub", "group", "a/b", "manual"),
+			ResourceID{SubscriptionID: "sub", ResourceGroupName: "group", WorkflowName: "a/b", TriggerName: "manual"},
+		},
+		{
+			"/subscriptions/sub/resourcegroups/group/providers/microsoft.logic/workflows/flow/runs/1",
+			ResourceID{SubscriptionID: "sub", ResourceGroupName: "group", WorkflowName: "flow", RunName: "1"},
+		},
+	}
+	for i, testCase := range testCases {
+		got, err := ParseResourceID(testCase.id)
+		if err != nil {
+			t.Fatalf("Test %d: ParseResourceID(%q): %v", i+1, testCase.id, err)
+		}
+		if got != testCase.want {
+			t.Fatalf("Test %d: ParseResourceID(%q)=%+v, want %+v", i+1, testCase.id, got, testCase.want)
+		}
+	}
+
+	if got, want := WorkflowRunID("sub", "my group", "flow", "1"), "/subscriptions/sub/resourceGroups/my%20group/providers/Microsoft.Logic/workflows/flow/runs/1"; got != want {
+		t.Fatalf("WorkflowRunID()=%q, want %q", got, want)
+	}
+}
+
+func TestParseResourceIDInvalid(t *testing.T) {
+	for _, id := range []string{
+		"",
+		"/subscriptions/sub",
+		"/subscriptions/sub/resourceGroups/group/providers/Microsoft.Web/sites/site",
+		"/subscriptions/sub/resourceGroups/group/providers/Microsoft.Logic/workflows/flow/versions/1",
+		"/subscriptions/sub/resourceGroups//providers/Microsoft.Logic/workflows/flow",
+	} {
+		if _, err := ParseResourceID(id); err == nil {
+			t.Errorf("ParseResourceID(%q): expected error", id)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// statusCode returns the HTTP status code of the response, or zero if no
+// response was received, e.g. because sending the request failed. The
+// methods of the clients set the Response of their result on the error
+// paths too, so that a failed call, such as a Cancel answered with 409
+// Conflict, still reports its status.
+func statusCode(resp autorest.Response) int {
+	if resp.Response == nil {
+		return 0
+	}
+	return resp.Response.StatusCode
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r CallbackURL) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r IntegrationAccount) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r IntegrationAccountAgreement) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r IntegrationAccountAgreementListResult) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r IntegrationAccountCertificate) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r IntegrationAccountCertificateListResult) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r IntegrationAccountListResult) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r IntegrationAccountMap) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r IntegrationAccountMapListResult) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r IntegrationAccountPartner) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r IntegrationAccountPartnerListResult) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r IntegrationAccountSchema) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r IntegrationAccountSchemaListResult) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r IntegrationAccountSession) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r IntegrationAccountSessionListResult) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r OperationListResult) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r SetObject) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r Workflow) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r WorkflowListResult) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r WorkflowRun) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r WorkflowRunAction) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r WorkflowRunActionListResult) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r WorkflowRunActionRepetition) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r WorkflowRunActionRepetitionListResult) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r WorkflowRunListResult) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r WorkflowTrigger) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r WorkflowTriggerCallbackURL) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r WorkflowTriggerHistory) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r WorkflowTriggerHistoryListResult) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r WorkflowTriggerListResult) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r WorkflowVersion) StatusCode() int {
+	return statusCode(r.Response)
+}
+
+// StatusCode returns the HTTP status code of the response, or zero if no
+// response was received.
+func (r WorkflowVersionListResult) StatusCode() int {
+	return statusCode(r.Response)
+}
@@ -0,0 +1,65 @@
+package logic
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+func TestStatusCode(t *testing.T) {
+	ok := autorest.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+	testCases := []struct {
+		result interface{ StatusCode() int }
+		want   int
+	}{
+		{WorkflowRun{Response: ok}, http.StatusOK},
+		{WorkflowRunListResult{Response: autorest.Response{Response: &http.Response{StatusCode: http.StatusAccepted}}}, http.StatusAccepted},
+		{IntegrationAccount{Response: ok}, http.StatusOK},
+		{WorkflowRun{}, 0},
+		{Workflow{Response: autorest.Response{}}, 0},
+		{WorkflowVersionListResult{}, 0},
+		{CallbackURL{}, 0},
+	}
+	for i, testCase := range testCases {
+		if got := testCase.result.StatusCode(); got != testCase.want {
+			t.Errorf("%d: %T.StatusCode()=%d, want %d", i, testCase.result, got, testCase.want)
+		}
+	}
+}
+
+func TestStatusCodeOnError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"WorkflowRunNotFound","message":"not found"}}`))
+		default:
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error":{"code":"WorkflowRunNotRunning","message":"not running"}}`))
+		}
+	}))
+	defer srv.Close()
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+
+	run, err := client.Get("group", "workflow", "run")
+	if err == nil || run.StatusCode() != http.StatusNotFound {
+		t.Errorf("Get(): got status %d and error %v, want the 404", run.StatusCode(), err)
+	}
+	list, err := client.List("group", "workflow", nil, "")
+	if err == nil || list.StatusCode() != http.StatusNotFound {
+		t.Errorf("List(): got status %d and error %v, want the 404", list.StatusCode(), err)
+	}
+	resp, err := client.Cancel("group", "workflow", "run")
+	if err == nil || statusCode(resp) != http.StatusConflict {
+		t.Errorf("Cancel(): got status %d and error %v, want the 409", statusCode(resp), err)
+	}
+
+	// Without a response, e.g. when the server cannot be reached, it is zero.
+	srv.Close()
+	client.RetryPolicy = nil
+	if run, err := client.Get("group", "workflow", "run"); err == nil || run.StatusCode() != 0 {
+		t.Errorf("Get() of a closed server: got status %d and error %v, want 0", run.StatusCode(), err)
+	}
+}
@@ -0,0 +1,219 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRetryBackoff caps the delay between two consecutive retries.
+const maxRetryBackoff = time.Minute
+
+// RetryPolicy decides whether a failed request is sent again. Its method set
+// is the one of management.RetryPolicy, so that the same policy can drive
+// both packages. See ManagementClient.RetryPolicy.
+type RetryPolicy interface {
+	// ShouldRetry reports whether req should be sent again after the given
+	// attempt, counted from zero, failed. statusCode is the HTTP status of
+	// the response, or zero if err is a transport error.
+	ShouldRetry(req *http.Request, statusCode int, err error, attempt int) bool
+}
+
+// DefaultRetryPolicy is the RetryPolicy of the clients created by New and
+// NewWithBaseURI. It retries transport errors and throttled or transient
+// 408, 429, 500, 502, 503 and 504 responses of the idempotent GET, HEAD, PUT
+// and DELETE requests. Other methods, notably POST, are only retried when
+// they could not connect, as retrying them could duplicate their effects.
+type DefaultRetryPolicy struct {
+	// MaxRetries is the maximum number of times a request is retried.
+	MaxRetries int
+}
+
+// ShouldRetry implements RetryPolicy.
+func (p DefaultRetryPolicy) ShouldRetry(req *http.Request, statusCode int, err error, attempt int) bool {
+	if attempt >= p.MaxRetries {
+		return false
+	}
+	if statusCode == 0 && isDialError(err) {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	switch statusCode {
+	case 0:
+		return err != nil
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isDialError reports whether err occurred while resolving the address of
+// the server or connecting to it, i.e. before anything was sent.
+func isDialError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// Do implements autorest.Sender, so that every request of the generated
+// clients is sent through it. Without a RetryPolicy it defers to the
+// autorest.Client, whose RetryAttempts then apply. Otherwise the request is
+// sent once, with the autorest retries disabled, and again for as long as
+// the policy asks for it: after the delay of the Retry-After header of the
+// failed response if any, else after a backoff growing exponentially from
+// RetryDuration. The body is buffered to send it again. In both cases a retry
+// is abandoned when the context of the request is done. The request is given
+// the CorrelationRequestID of the client first. With a BearerAuthorizer, a
+// request failing with 401 Unauthorized is sent once more with a new token.
+func (client ManagementClient) Do(r *http.Request) (*http.Response, error) {
+	if client.CorrelationRequestID != "" && r.Header.Get(CorrelationRequestIDHeader) == "" {
+		if r.Header == nil {
+			r.Header = make(http.Header)
+		}
+		r.Header.Set(CorrelationRequestIDHeader, client.CorrelationRequestID)
+	}
+	authorizer, ok := client.Authorizer.(*BearerAuthorizer)
+	if !ok {
+		return client.send(r)
+	}
+
+	// Buffer the body to send it again with a new token.
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(r.Body); err != nil {
+			return nil, err
+		}
+		r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	resp, err := client.send(r)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	authorizer.invalidate(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	resp.Body.Close()
+	if r.Body != nil {
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	return client.send(r)
+}
+
+// send implements Do, once the request is authorized.
+func (client ManagementClient) send(r *http.Request) (*http.Response, error) {
+	if client.RetryPolicy == nil {
+		// The autorest retries only stop waiting once Cancel is closed.
+		if r.Cancel == nil {
+			r.Cancel = r.Context().Done()
+		}
+		return client.Client.Do(r)
+	}
+
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(r.Body); err != nil {
+			return nil, err
+		}
+		r.Body.Close()
+	}
+
+	sender := client.Client
+	sender.RetryAttempts = 0
+	for attempt := 0; ; attempt++ {
+		if r.Body != nil {
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		resp, err := sender.Do(r)
+
+		statusCode := 0
+		if err == nil {
+			statusCode = resp.StatusCode
+			if statusCode < http.StatusBadRequest {
+				return resp, nil
+			}
+		}
+		if !client.RetryPolicy.ShouldRetry(r, statusCode, err, attempt) {
+			return resp, err
+		}
+
+		delay := retryAfter(resp, time.Now())
+		if delay <= 0 {
+			delay = client.backoff(attempt)
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-r.Context().Done():
+			t.Stop()
+			return nil, r.Context().Err()
+		}
+	}
+}
+
+// backoff returns the delay before the given retry attempt, starting at zero.
+func (client ManagementClient) backoff(attempt int) time.Duration {
+	d := maxRetryBackoff
+	if attempt < 32 {
+		if exp := client.RetryDuration << uint(attempt); exp >= 0 && exp < maxRetryBackoff {
+			d = exp
+		}
+	}
+	return d
+}
+
+// retryAfter returns the delay requested by the Retry-After header of resp,
+// given either in seconds or as an HTTP date, or zero if there is none.
+func retryAfter(resp *http.Response, now time.Time) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	var d time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		d = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		d = date.Sub(now)
+	}
+	if d > maxRetryBackoff {
+		d = maxRetryBackoff
+	}
+	return d
+}
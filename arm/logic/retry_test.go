@@ -0,0 +1,84 @@
+package logic
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		switch len(bodies) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			fmt.Fprint(w, `{"name":"workflow"}`)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowsClientWithBaseURI(srv.URL, "subscription")
+	client.RetryDuration = time.Millisecond
+	workflow, err := client.CreateOrUpdate("group", "workflow", Workflow{Name: to.StringPtr("workflow")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := to.String(workflow.Name); name != "workflow" {
+		t.Fatalf("got workflow %q, want workflow", name)
+	}
+	if len(bodies) != 3 {
+		t.Fatalf("got %d attempts, want 3", len(bodies))
+	}
+	for _, body := range bodies[1:] {
+		if body != bodies[0] {
+			t.Fatalf("got retried body %q, want %q", body, bodies[0])
+		}
+	}
+
+	// POST is not idempotent and thus not retried.
+	bodies = nil
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodies = append(bodies, "")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	if _, err := client.Enable("group", "workflow"); err == nil {
+		t.Fatal("Enable() succeeded, want the 503 error")
+	}
+	if len(bodies) != 1 {
+		t.Fatalf("got %d attempts of a POST, want 1", len(bodies))
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2017, 5, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"3600", maxRetryBackoff},
+		{now.Add(10 * time.Second).Format(http.TimeFormat), 10 * time.Second},
+		{"soon", 0},
+	}
+	for _, c := range cases {
+		resp := &http.Response{Header: http.Header{}}
+		if c.value != "" {
+			resp.Header.Set("Retry-After", c.value)
+		}
+		if got := retryAfter(resp, now); got != c.want {
+			t.Errorf("retryAfter(%q)=%v, want %v", c.value, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// Send sends req through Do and checks that the response has one of the
+// expected status codes, http.StatusOK if none is given; otherwise the body
+// is closed and the error it reports returned, as a *LogicError if it has
+// one. Send honors the context of req, set with http.Request.WithContext:
+// once it is cancelled or its deadline passes, the call returns its error
+// promptly, be it while connecting, waiting for the response or waiting to
+// retry the request.
+func (client ManagementClient) Send(req *http.Request, expectedStatus ...int) (*http.Response, error) {
+	ctx := req.Context()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(expectedStatus) == 0 {
+		expectedStatus = []int{http.StatusOK}
+	}
+
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return resp, ctxErr
+		}
+		return resp, err
+	}
+	if err := autorest.Respond(resp, withErrorUnlessStatusCode(expectedStatus...)); err != nil {
+		resp.Body.Close()
+		return resp, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,91 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSend(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/accepted":
+			w.WriteHeader(http.StatusAccepted)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"WorkflowNotFound","message":"not found"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewWithBaseURI(srv.URL, "subscription")
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/accepted", nil)
+	if _, err := client.Send(req, http.StatusOK, http.StatusAccepted); err != nil {
+		t.Fatal(err)
+	}
+	req, _ = http.NewRequest(http.MethodGet, srv.URL+"/missing", nil)
+	_, err := client.Send(req)
+	var logicErr *LogicError
+	if !errors.As(err, &logicErr) || logicErr.Code != "WorkflowNotFound" {
+		t.Fatalf("got error %v, want the WorkflowNotFound LogicError", err)
+	}
+}
+
+func TestSendContext(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/hang":
+			select {
+			case <-r.Context().Done():
+			case <-done:
+			}
+		case "/throttled":
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	withPolicy := NewWithBaseURI(srv.URL, "subscription")
+	withoutPolicy := withPolicy
+	withoutPolicy.RetryPolicy = nil
+	withoutPolicy.RetryDuration = time.Minute
+	cases := []struct {
+		name   string
+		client ManagementClient
+		path   string
+	}{
+		{"waiting for the response", withPolicy, "/hang"},
+		{"waiting to retry", withPolicy, "/throttled"},
+		{"waiting for an autorest retry", withoutPolicy, "/unavailable"},
+	}
+	for _, c := range cases {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+c.path, nil)
+		start := time.Now()
+		_, err := c.client.Send(req.WithContext(ctx))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: got error %v, want %v", c.name, err, context.Canceled)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("%s: Send returned after %v", c.name, elapsed)
+		}
+		cancel()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/hang", nil)
+	if _, err := withPolicy.Send(req.WithContext(ctx)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
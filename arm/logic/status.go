@@ -0,0 +1,56 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// IsTerminal reports whether the status is final, i.e. a run or an action
+// with such status will not change it anymore. Unknown statuses, which the
+// service may introduce in newer API versions, are not terminal.
+func (s WorkflowStatus) IsTerminal() bool {
+	switch s {
+	case WorkflowStatusAborted,
+		WorkflowStatusCancelled,
+		WorkflowStatusFailed,
+		WorkflowStatusFaulted,
+		WorkflowStatusIgnored,
+		WorkflowStatusSkipped,
+		WorkflowStatusSucceeded,
+		WorkflowStatusTimedOut:
+		return true
+	default:
+		return false
+	}
+}
+
+// isKnown reports whether the status is one of the WorkflowStatus constants.
+func (s WorkflowStatus) isKnown() bool {
+	switch s {
+	case WorkflowStatusAborted,
+		WorkflowStatusCancelled,
+		WorkflowStatusFailed,
+		WorkflowStatusFaulted,
+		WorkflowStatusIgnored,
+		WorkflowStatusNotSpecified,
+		WorkflowStatusPaused,
+		WorkflowStatusRunning,
+		WorkflowStatusSkipped,
+		WorkflowStatusSucceeded,
+		WorkflowStatusSuspended,
+		WorkflowStatusTimedOut,
+		WorkflowStatusWaiting:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,31 @@
+package logic
+
+import "testing"
+
+func TestWorkflowStatusIsTerminal(t *testing.T) {
+	testCases := []struct {
+		status WorkflowStatus
+		want   bool
+	}{
+		{WorkflowStatusAborted, true},
+		{WorkflowStatusCancelled, true},
+		{WorkflowStatusFailed, true},
+		{WorkflowStatusFaulted, true},
+		{WorkflowStatusIgnored, true},
+		{WorkflowStatusSkipped, true},
+		{WorkflowStatusSucceeded, true},
+		{WorkflowStatusTimedOut, true},
+		{WorkflowStatusNotSpecified, false},
+		{WorkflowStatusPaused, false},
+		{WorkflowStatusRunning, false},
+		{WorkflowStatusSuspended, false},
+		{WorkflowStatusWaiting, false},
+		{WorkflowStatus("Rebooting"), false},
+		{WorkflowStatus(""), false},
+	}
+	for _, testCase := range testCases {
+		if got := testCase.status.IsTerminal(); got != testCase.want {
+			t.Errorf("%q.IsTerminal()=%t, want %t", testCase.status, got, testCase.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"errors"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// SetUserAgentSuffix makes the client identify the calling application in the
+// User-Agent header of the requests, for the Azure-side diagnostics and
+// telemetry to attribute them to it: the header becomes the default one, see
+// UserAgent, followed by suffix, e.g. "myapp/1.2.0". Setting a suffix again
+// replaces the previous one. The control characters of suffix, CR and LF
+// included, are replaced with spaces, so it cannot inject headers. It fails
+// if suffix is empty once sanitized.
+func (client *ManagementClient) SetUserAgentSuffix(suffix string) error {
+	suffix = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f {
+			return ' '
+		}
+		return r
+	}, suffix))
+	if suffix == "" {
+		return errors.New("logic: empty user agent suffix")
+	}
+	client.UserAgent = autorest.NewClientWithUserAgent(UserAgent()).UserAgent + " " + suffix
+	return nil
+}
@@ -0,0 +1,38 @@
+package logic
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSetUserAgentSuffix(t *testing.T) {
+	var userAgent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowsClientWithBaseURI(srv.URL, "subscription")
+	if err := client.SetUserAgentSuffix("first/1.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetUserAgentSuffix("myapp/1.2.0\r\nX-Injected: 1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Get("group", "workflow"); err != nil {
+		t.Fatal(err)
+	}
+	if want := UserAgent() + " myapp/1.2.0  X-Injected: 1"; !strings.HasSuffix(userAgent, want) {
+		t.Fatalf("got User-Agent %q, want it to end with %q", userAgent, want)
+	}
+	if strings.Contains(userAgent, "first/1.0") {
+		t.Fatalf("got User-Agent %q, want the first suffix replaced", userAgent)
+	}
+
+	if err := client.SetUserAgentSuffix(" \r\n"); err == nil {
+		t.Fatal("SetUserAgentSuffix() with an empty suffix succeeded")
+	}
+}
@@ -0,0 +1,177 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// WorkflowRunActionRepetitionsClient is the client for the repetitions of
+// the workflow run actions, i.e. the iterations of the foreach and until
+// loops of a run.
+type WorkflowRunActionRepetitionsClient struct {
+	ManagementClient
+}
+
+// NewWorkflowRunActionRepetitionsClient creates an instance of the
+// WorkflowRunActionRepetitionsClient client.
+func NewWorkflowRunActionRepetitionsClient(subscriptionID string) WorkflowRunActionRepetitionsClient {
+	return NewWorkflowRunActionRepetitionsClientWithBaseURI(DefaultBaseURI, subscriptionID)
+}
+
+// NewWorkflowRunActionRepetitionsClientWithBaseURI creates an instance of the
+// WorkflowRunActionRepetitionsClient client.
+func NewWorkflowRunActionRepetitionsClientWithBaseURI(baseURI string, subscriptionID string) WorkflowRunActionRepetitionsClient {
+	return WorkflowRunActionRepetitionsClient{NewWithBaseURI(baseURI, subscriptionID)}
+}
+
+// Get gets a workflow run action repetition.
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name. runName is the workflow run name. actionName is the workflow action
+// name. repetitionName is the workflow repetition.
+func (client WorkflowRunActionRepetitionsClient) Get(resourceGroupName string, workflowName string, runName string, actionName string, repetitionName string) (result WorkflowRunActionRepetition, err error) {
+	req, err := client.GetPreparer(resourceGroupName, workflowName, runName, actionName, repetitionName)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowRunActionRepetitionsClient", "Get", nil, "Failure preparing request")
+		return
+	}
+
+	resp, err := client.GetSender(req)
+	if err != nil {
+		result.Response = autorest.Response{Response: resp}
+		err = autorest.NewErrorWithError(err, "logic.WorkflowRunActionRepetitionsClient", "Get", resp, "Failure sending request: %s", describeRequest(req))
+		return
+	}
+
+	result, err = client.GetResponder(resp)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowRunActionRepetitionsClient", "Get", resp, "Failure responding to request: %s", describeRequest(req))
+	}
+
+	return
+}
+
+// GetPreparer prepares the Get request.
+func (client WorkflowRunActionRepetitionsClient) GetPreparer(resourceGroupName string, workflowName string, runName string, actionName string, repetitionName string) (*http.Request, error) {
+	pathParameters := map[string]interface{}{
+		"actionName":        autorest.Encode("path", actionName),
+		"repetitionName":    autorest.Encode("path", repetitionName),
+		"resourceGroupName": autorest.Encode("path", resourceGroupName),
+		"runName":           autorest.Encode("path", runName),
+		"subscriptionId":    autorest.Encode("path", client.SubscriptionID),
+		"workflowName":      autorest.Encode("path", workflowName),
+	}
+
+	queryParameters := map[string]interface{}{
+		"api-version": client.APIVersion,
+	}
+
+	preparer := autorest.CreatePreparer(
+		autorest.AsGet(),
+		autorest.WithBaseURL(client.BaseURI),
+		autorest.WithPathParameters("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Logic/workflows/{workflowName}/runs/{runName}/actions/{actionName}/repetitions/{repetitionName}", pathParameters),
+		autorest.WithQueryParameters(queryParameters))
+	return preparer.Prepare(&http.Request{})
+}
+
+// GetSender sends the Get request. The method will close the
+// http.Response Body if it receives an error.
+func (client WorkflowRunActionRepetitionsClient) GetSender(req *http.Request) (*http.Response, error) {
+	return autorest.SendWithSender(client, req)
+}
+
+// GetResponder handles the response to the Get request. The method always
+// closes the http.Response Body.
+func (client WorkflowRunActionRepetitionsClient) GetResponder(resp *http.Response) (result WorkflowRunActionRepetition, err error) {
+	err = autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		withErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByUnmarshallingJSON(&result),
+		autorest.ByClosing())
+	result.Response = autorest.Response{Response: resp}
+	return
+}
+
+// List gets all the repetitions of a workflow run action.
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name. runName is the workflow run name. actionName is the workflow action
+// name.
+func (client WorkflowRunActionRepetitionsClient) List(resourceGroupName string, workflowName string, runName string, actionName string) (result WorkflowRunActionRepetitionListResult, err error) {
+	req, err := client.ListPreparer(resourceGroupName, workflowName, runName, actionName)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowRunActionRepetitionsClient", "List", nil, "Failure preparing request")
+		return
+	}
+
+	resp, err := client.ListSender(req)
+	if err != nil {
+		result.Response = autorest.Response{Response: resp}
+		err = autorest.NewErrorWithError(err, "logic.WorkflowRunActionRepetitionsClient", "List", resp, "Failure sending request: %s", describeRequest(req))
+		return
+	}
+
+	result, err = client.ListResponder(resp)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowRunActionRepetitionsClient", "List", resp, "Failure responding to request: %s", describeRequest(req))
+	}
+
+	return
+}
+
+// ListPreparer prepares the List request.
+func (client WorkflowRunActionRepetitionsClient) ListPreparer(resourceGroupName string, workflowName string, runName string, actionName string) (*http.Request, error) {
+	pathParameters := map[string]interface{}{
+		"actionName":        autorest.Encode("path", actionName),
+		"resourceGroupName": autorest.Encode("path", resourceGroupName),
+		"runName":           autorest.Encode("path", runName),
+		"subscriptionId":    autorest.Encode("path", client.SubscriptionID),
+		"workflowName":      autorest.Encode("path", workflowName),
+	}
+
+	queryParameters := map[string]interface{}{
+		"api-version": client.APIVersion,
+	}
+
+	preparer := autorest.CreatePreparer(
+		autorest.AsGet(),
+		autorest.WithBaseURL(client.BaseURI),
+		autorest.WithPathParameters("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Logic/workflows/{workflowName}/runs/{runName}/actions/{actionName}/repetitions", pathParameters),
+		autorest.WithQueryParameters(queryParameters))
+	return preparer.Prepare(&http.Request{})
+}
+
+// ListSender sends the List request. The method will close the
+// http.Response Body if it receives an error.
+func (client WorkflowRunActionRepetitionsClient) ListSender(req *http.Request) (*http.Response, error) {
+	return autorest.SendWithSender(client, req)
+}
+
+// ListResponder handles the response to the List request. The method always
+// closes the http.Response Body.
+func (client WorkflowRunActionRepetitionsClient) ListResponder(resp *http.Response) (result WorkflowRunActionRepetitionListResult, err error) {
+	err = autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		withErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByUnmarshallingJSON(&result),
+		autorest.ByClosing())
+	result.Response = autorest.Response{Response: resp}
+	return
+}
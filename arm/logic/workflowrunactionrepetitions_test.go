@@ -0,0 +1,49 @@
+package logic
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+func TestWorkflowRunActionRepetitions(t *testing.T) {
+	const path = "/subscriptions/subscription/resourceGroups/group/providers/Microsoft.Logic/workflows/workflow/runs/run/actions/action/repetitions"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case path:
+			fmt.Fprint(w, `{"value":[{"name":"000000","properties":{"status":"Succeeded","repetitionIndexes":[{"scopeName":"For_each","itemIndex":0}]}},{"name":"000001"}]}`)
+		case path + "/000001":
+			fmt.Fprint(w, `{"name":"000001","properties":{"status":"Failed","startTime":"2017-05-01T12:00:00Z","repetitionIndexes":[{"scopeName":"For_each","itemIndex":1}]}}`)
+		default:
+			t.Errorf("unexpected request for %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	client := NewWorkflowRunActionRepetitionsClientWithBaseURI(srv.URL, "subscription")
+
+	list, err := client.List("group", "workflow", "run", "action")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Value == nil || len(*list.Value) != 2 {
+		t.Fatalf("got repetitions %+v, want 2", list.Value)
+	}
+	if first := (*list.Value)[0]; first.Status != WorkflowStatusSucceeded || to.String((*first.RepetitionIndexes)[0].ScopeName) != "For_each" {
+		t.Fatalf("got first repetition %+v, want a succeeded For_each iteration", first.WorkflowRunActionRepetitionProperties)
+	}
+
+	repetition, err := client.Get("group", "workflow", "run", "action", "000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repetition.Status != WorkflowStatusFailed || repetition.StartTime == nil {
+		t.Fatalf("got repetition %+v, want a failed one with a start time", repetition.WorkflowRunActionRepetitionProperties)
+	}
+	if index := (*repetition.RepetitionIndexes)[0]; index.ItemIndex == nil || *index.ItemIndex != 1 {
+		t.Fatalf("got repetition index %+v, want item 1", index)
+	}
+}
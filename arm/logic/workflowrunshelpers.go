@@ -0,0 +1,378 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+// ErrNoTriggerOutput is returned by WorkflowRun.TriggerOutputInto when the
+// run carries no trigger outputs.
+var ErrNoTriggerOutput = errors.New("logic: workflow run has no trigger output")
+
+// ErrNoRuns is returned by WorkflowRunsClient.GetLatest when the workflow
+// has never run.
+var ErrNoRuns = errors.New("logic: workflow has no runs")
+
+// ErrNoResults is returned by WorkflowRunsClient.ListRequireNonEmpty when no
+// run matches the request.
+var ErrNoResults = errors.New("logic: no workflow runs match the request")
+
+var (
+	// ErrTooManyPages is returned by the pagination helpers when the results
+	// span more pages than ManagementClient.MaxPages allows.
+	ErrTooManyPages = errors.New("logic: too many result pages")
+
+	// ErrNextLinkLoop is returned by the pagination helpers when the service
+	// returns a next page link which has already been followed.
+	ErrNextLinkLoop = errors.New("logic: next page link loops")
+)
+
+const (
+	// MaxWorkflowRunsTop is the largest page size the service accepts when
+	// listing workflow runs.
+	MaxWorkflowRunsTop = 250
+
+	// DefaultWorkflowRunsTop is the page size used by ListRecent.
+	DefaultWorkflowRunsTop = 30
+
+	// CancelAllRunningConcurrency is the maximum number of cancel requests
+	// CancelAllRunning has in flight at any time.
+	CancelAllRunningConcurrency = 4
+)
+
+// newestFirst is the $orderby expression listing the runs most recently
+// started first.
+const newestFirst = "startTime desc"
+
+// ListTop gets the n most recent runs of a workflow, ordered by their start
+// time, newest first. n is clamped to the range [1, MaxWorkflowRunsTop].
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name.
+func (client WorkflowRunsClient) ListTop(resourceGroupName string, workflowName string, n int) (result WorkflowRunListResult, err error) {
+	top := int32(clampTop(n, MaxWorkflowRunsTop))
+	return client.ListWithQuery(resourceGroupName, workflowName, ODataQuery{Top: &top, OrderBy: newestFirst})
+}
+
+// ListRecent gets the most recent runs of a workflow, newest first, using
+// DefaultWorkflowRunsTop as the page size. Use ListNextResults to page
+// through older runs.
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name.
+func (client WorkflowRunsClient) ListRecent(resourceGroupName string, workflowName string) (result WorkflowRunListResult, err error) {
+	return client.ListTop(resourceGroupName, workflowName, DefaultWorkflowRunsTop)
+}
+
+// GetLatest gets the most recently started run of a workflow, or ErrNoRuns
+// if it has never run. The Response of the returned run is that of the list
+// request.
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name.
+func (client WorkflowRunsClient) GetLatest(resourceGroupName string, workflowName string) (result WorkflowRun, err error) {
+	top := int32(1)
+	page, err := client.ListWithQuery(resourceGroupName, workflowName, ODataQuery{Top: &top, OrderBy: newestFirst})
+	result.Response = page.Response
+	if err != nil {
+		return result, err
+	}
+	if page.Value == nil || len(*page.Value) == 0 {
+		return result, ErrNoRuns
+	}
+	result = (*page.Value)[0]
+	result.Response = page.Response
+	return result, nil
+}
+
+// ListByStatus gets the runs of a workflow which are in the given status,
+// e.g. WorkflowStatusFailed. top, if not nil, limits the number of runs per
+// page; use ListNextResults to page through the rest. A status other than
+// the WorkflowStatus constants is rejected without sending the request.
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name. status is the status of the runs to list.
+func (client WorkflowRunsClient) ListByStatus(resourceGroupName string, workflowName string, status WorkflowStatus, top *int32) (result WorkflowRunListResult, err error) {
+	if !status.isKnown() {
+		return result, fmt.Errorf("logic: unknown workflow status %q", status)
+	}
+	return client.List(resourceGroupName, workflowName, top, statusFilter(status))
+}
+
+// ListRequireNonEmpty gets a list of workflow runs, like List, but fails with
+// ErrNoResults if no run matches, e.g. because of a typo in the status of
+// filter, instead of returning an empty list. A first page without runs but
+// with a link to the next page is returned as is.
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name. top is the number of items to be included in the result. filter is the
+// filter to apply on the operation.
+func (client WorkflowRunsClient) ListRequireNonEmpty(resourceGroupName string, workflowName string, top *int32, filter string) (result WorkflowRunListResult, err error) {
+	result, err = client.List(resourceGroupName, workflowName, top, filter)
+	if err != nil {
+		return result, err
+	}
+	if (result.Value == nil || len(*result.Value) == 0) && (result.NextLink == nil || *result.NextLink == "") {
+		return result, ErrNoResults
+	}
+	return result, nil
+}
+
+// statusFilter returns the OData filter selecting runs in the given status.
+func statusFilter(status WorkflowStatus) string {
+	return fmt.Sprintf("status eq '%s'", status)
+}
+
+func clampTop(n, max int) int {
+	switch {
+	case n < 1:
+		return 1
+	case n > max:
+		return max
+	default:
+		return n
+	}
+}
+
+// TriggerOutputInto decodes the outputs of the trigger which started the run
+// into v. When the outputs wrap the payload in a "body" property, as HTTP and
+// request triggers do, only the body is decoded.
+func (run WorkflowRun) TriggerOutputInto(v interface{}) error {
+	if run.WorkflowRunProperties == nil || run.Trigger == nil || run.Trigger.Outputs == nil {
+		return ErrNoTriggerOutput
+	}
+
+	var output interface{} = *run.Trigger.Outputs
+	if body, ok := (*run.Trigger.Outputs)["body"]; ok {
+		output = body
+	}
+
+	b, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// CancelAllRunning cancels every run of a workflow which is currently
+// running and returns the names of the runs it cancelled, sorted. The cancel
+// requests are sent concurrently, but at most CancelAllRunningConcurrency at
+// a time, to avoid being throttled.
+//
+// If some of the runs could not be cancelled, the names of the ones which
+// were are returned together with an error wrapping the first failure. When
+// ctx is done, no further requests are started and ctx.Err() is returned;
+// requests already in flight are allowed to complete and reported.
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name.
+func (client WorkflowRunsClient) CancelAllRunning(ctx context.Context, resourceGroupName string, workflowName string) (cancelled []string, err error) {
+	names, err := client.listRunning(ctx, resourceGroupName, workflowName)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failed   int
+		firstErr error
+		sem      = make(chan struct{}, CancelAllRunningConcurrency)
+	)
+loop:
+	for _, name := range names {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break loop
+		}
+		// Check again, as select picks randomly among the ready cases.
+		if ctx.Err() != nil {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(name string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			_, err := client.Cancel(resourceGroupName, workflowName, name)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				failed++
+				return
+			}
+			cancelled = append(cancelled, name)
+		}(name)
+	}
+	wg.Wait()
+
+	sort.Strings(cancelled)
+	switch {
+	case ctx.Err() != nil:
+		return cancelled, ctx.Err()
+	case firstErr != nil:
+		return cancelled, fmt.Errorf("logic: failed to cancel %d of %d running runs: %w", failed, len(names), firstErr)
+	}
+	return cancelled, nil
+}
+
+// listRunning returns the names of the running runs of a workflow.
+func (client WorkflowRunsClient) listRunning(ctx context.Context, resourceGroupName string, workflowName string) ([]string, error) {
+	runs, err := client.listAll(ctx, resourceGroupName, workflowName, nil, statusFilter(WorkflowStatusRunning))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, run := range runs {
+		if name := to.String(run.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// ListComplete gets all the runs of a workflow, following the pagination of
+// the results. It returns the runs listed so far together with the error if
+// fetching one of the pages fails, so the returned slice may be incomplete
+// when err is not nil.
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name. top is the number of items to be included in the result per page.
+// filter is the filter to apply on the operation.
+func (client WorkflowRunsClient) ListComplete(resourceGroupName string, workflowName string, top *int32, filter string) ([]WorkflowRun, error) {
+	return client.listAll(context.Background(), resourceGroupName, workflowName, top, filter)
+}
+
+// listAll walks all the pages of the runs of a workflow, stopping early when
+// ctx is done. The runs listed before a failure are returned with the error.
+func (client WorkflowRunsClient) listAll(ctx context.Context, resourceGroupName string, workflowName string, top *int32, filter string) ([]WorkflowRun, error) {
+	page, err := client.List(resourceGroupName, workflowName, top, filter)
+
+	var runs []WorkflowRun
+	guard := client.newPageGuard()
+	for {
+		if err != nil {
+			return runs, err
+		}
+		if page.Value != nil {
+			runs = append(runs, *page.Value...)
+		}
+		next := to.String(page.NextLink)
+		if next == "" {
+			return runs, nil
+		}
+		if err := guard.follow(next); err != nil {
+			return runs, err
+		}
+		if err := ctx.Err(); err != nil {
+			return runs, err
+		}
+		page, err = client.ListNextResults(page)
+	}
+}
+
+// ListChannel streams all the runs of a workflow, following the pagination
+// of the results like ListComplete, but fetching the next page only once
+// the runs of the current one have been received, so that at most one page
+// is held in memory. Both channels are closed when the listing ends; if it
+// fails, or ctx is done first, the error, e.g. ctx.Err(), is sent on the
+// error channel before. The goroutine exits when ctx is done even if
+// nobody receives from the channels anymore.
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name. top is the number of items to be included in the result per page.
+// filter is the filter to apply on the operation.
+func (client WorkflowRunsClient) ListChannel(ctx context.Context, resourceGroupName string, workflowName string, top *int32, filter string) (<-chan WorkflowRun, <-chan error) {
+	runs := make(chan WorkflowRun)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(runs)
+
+		page, err := client.List(resourceGroupName, workflowName, top, filter)
+		guard := client.newPageGuard()
+		for {
+			if err == nil {
+				err = ctx.Err()
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+			if page.Value != nil {
+				for _, run := range *page.Value {
+					select {
+					case runs <- run:
+					case <-ctx.Done():
+						errs <- ctx.Err()
+						return
+					}
+				}
+			}
+			next := to.String(page.NextLink)
+			if next == "" {
+				return
+			}
+			if err = guard.follow(next); err == nil {
+				page, err = client.ListNextResults(page)
+			}
+		}
+	}()
+	return runs, errs
+}
+
+// pageGuard protects the pagination helpers against a service returning
+// endless or looping next page links.
+type pageGuard struct {
+	max   int
+	pages int
+	seen  map[string]bool
+}
+
+func (client ManagementClient) newPageGuard() *pageGuard {
+	max := client.MaxPages
+	if max <= 0 {
+		max = DefaultMaxPages
+	}
+	return &pageGuard{max: max, pages: 1, seen: map[string]bool{}}
+}
+
+// follow records that the next page link is about to be fetched.
+func (g *pageGuard) follow(nextLink string) error {
+	if g.seen[nextLink] {
+		return ErrNextLinkLoop
+	}
+	if g.pages >= g.max {
+		return ErrTooManyPages
+	}
+	g.seen[nextLink] = true
+	g.pages++
+	return nil
+}
@@ -0,0 +1,368 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCancelAllRunning(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		inFlight int
+		maxSeen  int
+		release  = make(chan struct{})
+	)
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Query().Get("page") == "":
+			if got, want := r.URL.Query().Get("$filter"), "status eq 'Running'"; got != want {
+				t.Errorf("got filter %q, want %q", got, want)
+			}
+			fmt.Fprintf(w, `{"value":[{"name":"a"},{"name":"b"},{"name":"c"}],"nextLink":%q}`, srv.URL+r.URL.Path+"?page=2")
+		case r.Method == http.MethodGet:
+			fmt.Fprint(w, `{"value":[{"name":"d"},{"name":"e"},{"name":"f"}]}`)
+		case strings.HasSuffix(r.URL.Path, "/cancel"):
+			mu.Lock()
+			inFlight++
+			if inFlight > maxSeen {
+				maxSeen = inFlight
+			}
+			if maxSeen == CancelAllRunningConcurrency {
+				select {
+				case <-release:
+				default:
+					close(release)
+				}
+			}
+			mu.Unlock()
+			<-release
+
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+			if strings.HasSuffix(r.URL.Path, "/runs/e/cancel") {
+				w.WriteHeader(http.StatusConflict)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+	cancelled, err := client.CancelAllRunning(context.Background(), "group", "workflow")
+	if err == nil || !strings.Contains(err.Error(), "1 of 6") {
+		t.Fatalf("got error %v, want a failure for 1 of 6 runs", err)
+	}
+	if want := []string{"a", "b", "c", "d", "f"}; !reflect.DeepEqual(cancelled, want) {
+		t.Fatalf("got cancelled %v, want %v", cancelled, want)
+	}
+	if maxSeen > CancelAllRunningConcurrency {
+		t.Fatalf("got %d concurrent cancels, want at most %d", maxSeen, CancelAllRunningConcurrency)
+	}
+}
+
+func TestCancelAllRunningCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var (
+		mu       sync.Mutex
+		requests int
+		release  = make(chan struct{})
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `{"value":[{"name":"a"},{"name":"b"},{"name":"c"},{"name":"d"},{"name":"e"},{"name":"f"}]}`)
+			return
+		}
+		// Cancel once the in-flight requests fill the semaphore.
+		mu.Lock()
+		requests++
+		if requests == CancelAllRunningConcurrency {
+			cancel()
+			close(release)
+		}
+		mu.Unlock()
+		<-release
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+	cancelled, err := client.CancelAllRunning(ctx, "group", "workflow")
+	if err != context.Canceled {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if len(cancelled) != CancelAllRunningConcurrency || requests != CancelAllRunningConcurrency {
+		t.Fatalf("got %d requests and runs %v cancelled, want only the %d in flight", requests, cancelled, CancelAllRunningConcurrency)
+	}
+}
+
+func TestListCompletePartial(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			fmt.Fprintf(w, `{"value":[{"name":"a"},{"name":"b"}],"nextLink":%q}`, srv.URL+r.URL.Path+"?page=2")
+		case "2":
+			fmt.Fprintf(w, `{"value":[{"name":"c"}],"nextLink":%q}`, srv.URL+r.URL.Path+"?page=3")
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+	runs, err := client.ListComplete("group", "workflow", nil, "")
+	if err == nil {
+		t.Fatal("expected the third page to fail")
+	}
+	var names []string
+	for _, run := range runs {
+		names = append(names, *run.Name)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("got runs %v, want %v", names, want)
+	}
+}
+
+func TestListCompleteGuards(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next := "/endless?page=" + r.URL.Query().Get("page") + "0"
+		if strings.HasPrefix(r.URL.Path, "/looping") {
+			next = "/looping?page=1"
+		}
+		fmt.Fprintf(w, `{"value":[{"name":"run"}],"nextLink":%q}`, srv.URL+next)
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL+"/endless", "subscription")
+	client.MaxPages = 5
+	runs, err := client.ListComplete("group", "workflow", nil, "")
+	if err != ErrTooManyPages || len(runs) != 5 {
+		t.Fatalf("got %d runs, error %v, want 5 runs, error %v", len(runs), err, ErrTooManyPages)
+	}
+
+	client = NewWorkflowRunsClientWithBaseURI(srv.URL+"/looping", "subscription")
+	if _, err := client.ListComplete("group", "workflow", nil, ""); err != ErrNextLinkLoop {
+		t.Fatalf("got error %v, want %v", err, ErrNextLinkLoop)
+	}
+}
+
+func TestListTop(t *testing.T) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, `{"value":[]}`)
+	}))
+	defer srv.Close()
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+
+	testCases := []struct {
+		n       int
+		wantTop string
+	}{
+		{10, "10"},
+		{MaxWorkflowRunsTop, "250"},
+		{MaxWorkflowRunsTop + 1, "250"},
+		{0, "1"},
+		{-5, "1"},
+	}
+	for _, testCase := range testCases {
+		if _, err := client.ListTop("group", "workflow", testCase.n); err != nil {
+			t.Fatalf("ListTop(%d)=%v", testCase.n, err)
+		}
+		if got := query.Get("$top"); got != testCase.wantTop {
+			t.Errorf("ListTop(%d): got $top %q, want %q", testCase.n, got, testCase.wantTop)
+		}
+		if got := query.Get("$orderby"); got != "startTime desc" {
+			t.Errorf("ListTop(%d): got $orderby %q, want the newest runs first", testCase.n, got)
+		}
+	}
+
+	if _, err := client.ListRecent("group", "workflow"); err != nil {
+		t.Fatal(err)
+	}
+	if top, orderBy := query.Get("$top"), query.Get("$orderby"); top != "30" || orderBy != "startTime desc" {
+		t.Fatalf("ListRecent(): got $top %q and $orderby %q, want 30 newest first", top, orderBy)
+	}
+}
+
+func TestGetLatest(t *testing.T) {
+	empty := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query(); q.Get("$top") != "1" || q.Get("$orderby") != "startTime desc" {
+			t.Errorf("got query %q, want the single newest run", r.URL.RawQuery)
+		}
+		if empty {
+			fmt.Fprint(w, `{"value":[]}`)
+			return
+		}
+		fmt.Fprint(w, `{"value":[{"name":"latest"}]}`)
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+	run, err := client.GetLatest("group", "workflow")
+	if err != nil || run.Name == nil || *run.Name != "latest" {
+		t.Fatalf("GetLatest()=(%+v, %v), want the latest run", run, err)
+	}
+
+	empty = true
+	if _, err := client.GetLatest("group", "workflow"); err != ErrNoRuns {
+		t.Fatalf("got error %v, want %v", err, ErrNoRuns)
+	}
+}
+
+func TestTriggerOutputInto(t *testing.T) {
+	type order struct {
+		ID    string `json:"id"`
+		Total int    `json:"total"`
+	}
+	testCases := []struct {
+		run     string
+		want    order
+		wantErr error
+	}{
+		{`{"properties":{"trigger":{"outputs":{"headers":{"Content-Type":"application/json"},"body":{"id":"a","total":2}}}}}`, order{"a", 2}, nil},
+		{`{"properties":{"trigger":{"outputs":{"id":"b","total":3}}}}`, order{"b", 3}, nil},
+		{`{"properties":{"trigger":{"name":"manual"}}}`, order{}, ErrNoTriggerOutput},
+		{`{"properties":{}}`, order{}, ErrNoTriggerOutput},
+		{`{"name":"run"}`, order{}, ErrNoTriggerOutput},
+	}
+	for i, testCase := range testCases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, testCase.run)
+		}))
+		client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+		run, err := client.Get("group", "workflow", "run")
+		srv.Close()
+		if err != nil {
+			t.Fatalf("%d: Get()=%v", i, err)
+		}
+
+		var got order
+		if err := run.TriggerOutputInto(&got); err != testCase.wantErr {
+			t.Errorf("%d: TriggerOutputInto()=%v, want %v", i, err, testCase.wantErr)
+		}
+		if got != testCase.want {
+			t.Errorf("%d: got %+v, want %+v", i, got, testCase.want)
+		}
+	}
+}
+
+func TestListByStatus(t *testing.T) {
+	var filters []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filters = append(filters, r.URL.Query().Get("$filter"))
+		fmt.Fprint(w, `{"value":[]}`)
+	}))
+	defer srv.Close()
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+
+	for _, status := range []WorkflowStatus{WorkflowStatusFailed, WorkflowStatusTimedOut} {
+		if _, err := client.ListByStatus("group", "workflow", status, nil); err != nil {
+			t.Fatalf("ListByStatus(%s)=%v", status, err)
+		}
+	}
+	if want := []string{"status eq 'Failed'", "status eq 'TimedOut'"}; !reflect.DeepEqual(filters, want) {
+		t.Fatalf("got filters %q, want %q", filters, want)
+	}
+
+	filters = nil
+	for _, status := range []WorkflowStatus{"Faild", "Failed' or name eq 'x", ""} {
+		if _, err := client.ListByStatus("group", "workflow", status, nil); err == nil {
+			t.Errorf("ListByStatus(%q) succeeded, want an error", status)
+		}
+	}
+	if len(filters) != 0 {
+		t.Fatalf("got %d requests sent for invalid statuses", len(filters))
+	}
+}
+
+func TestListRequireNonEmpty(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("$filter") {
+		case "status eq 'Failed'":
+			fmt.Fprint(w, `{"value":[{"name":"failed"}]}`)
+		case "status eq 'Running'":
+			fmt.Fprintf(w, `{"value":[],"nextLink":"%s/next"}`, "http://"+r.Host)
+		default:
+			fmt.Fprint(w, `{"value":[]}`)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+	page, err := client.ListRequireNonEmpty("group", "workflow", nil, "status eq 'Failed'")
+	if err != nil || page.Value == nil || len(*page.Value) != 1 {
+		t.Fatalf("ListRequireNonEmpty()=(%+v, %v), want the failed run", page, err)
+	}
+	if _, err := client.ListRequireNonEmpty("group", "workflow", nil, "status eq 'Running'"); err != nil {
+		t.Fatalf("ListRequireNonEmpty() of an empty page with a next link: %v", err)
+	}
+	if _, err := client.ListRequireNonEmpty("group", "workflow", nil, "status eq 'Faild'"); err != ErrNoResults {
+		t.Fatalf("got error %v, want %v", err, ErrNoResults)
+	}
+	page, err = client.List("group", "workflow", nil, "status eq 'Faild'")
+	if err != nil || page.Value == nil || len(*page.Value) != 0 {
+		t.Fatalf("List()=(%+v, %v), want an empty list", page, err)
+	}
+}
+
+func TestListChannel(t *testing.T) {
+	var srv *httptest.Server
+	var mu sync.Mutex
+	var pages []string
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		mu.Lock()
+		pages = append(pages, page)
+		mu.Unlock()
+		switch page {
+		case "":
+			fmt.Fprintf(w, `{"value":[{"name":"a"},{"name":"b"}],"nextLink":%q}`, srv.URL+r.URL.Path+"?page=2")
+		case "2":
+			fmt.Fprintf(w, `{"value":[{"name":"c"}],"nextLink":%q}`, srv.URL+r.URL.Path+"?page=3")
+		default:
+			fmt.Fprint(w, `{"value":[{"name":"d"}]}`)
+		}
+	}))
+	defer srv.Close()
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+
+	runs, errs := client.ListChannel(context.Background(), "group", "workflow", nil, "")
+	var names []string
+	for run := range runs {
+		names = append(names, *run.Name)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("got runs %v, want %v", names, want)
+	}
+
+	// Cancelling after the first run stops the listing before the next page.
+	mu.Lock()
+	pages = nil
+	mu.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	runs, errs = client.ListChannel(ctx, "group", "workflow", nil, "")
+	<-runs
+	cancel()
+	if err := <-errs; err != context.Canceled {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(pages) != 1 {
+		t.Fatalf("got pages %q fetched, want only the first", pages)
+	}
+}
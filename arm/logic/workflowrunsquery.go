@@ -0,0 +1,104 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/validation"
+)
+
+// ODataQuery holds the optional OData query options of a list request. The
+// zero value requests the default, unsorted first page.
+type ODataQuery struct {
+	// Top is the maximum number of items to return.
+	Top *int32
+	// Skip is the number of items to skip; it must not be negative.
+	Skip *int32
+	// Filter is the $filter expression, e.g. "status eq 'Failed'".
+	Filter string
+	// OrderBy is the $orderby expression, e.g. "startTime desc".
+	OrderBy string
+}
+
+// ListWithQuery gets a list of workflow runs, like List, with the full set
+// of OData query options. Use ListNextResults to page through the results.
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name. query holds the query options.
+func (client WorkflowRunsClient) ListWithQuery(resourceGroupName string, workflowName string, query ODataQuery) (result WorkflowRunListResult, err error) {
+	if err := validation.Validate([]validation.Validation{
+		{TargetValue: query.Skip,
+			Constraints: []validation.Constraint{{Target: "query.Skip", Name: validation.Null, Rule: false,
+				Chain: []validation.Constraint{{Target: "query.Skip", Name: validation.InclusiveMinimum, Rule: 0, Chain: nil}}}}}}); err != nil {
+		return result, validation.NewErrorWithValidationError(err, "logic.WorkflowRunsClient", "ListWithQuery")
+	}
+
+	req, err := client.ListWithQueryPreparer(resourceGroupName, workflowName, query)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowRunsClient", "ListWithQuery", nil, "Failure preparing request")
+		return
+	}
+
+	resp, err := client.ListSender(req)
+	if err != nil {
+		result.Response = autorest.Response{Response: resp}
+		err = autorest.NewErrorWithError(err, "logic.WorkflowRunsClient", "ListWithQuery", resp, "Failure sending request: %s", describeRequest(req))
+		return
+	}
+
+	result, err = client.ListResponder(resp)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowRunsClient", "ListWithQuery", resp, "Failure responding to request: %s", describeRequest(req))
+	}
+
+	return
+}
+
+// ListWithQueryPreparer prepares the ListWithQuery request.
+func (client WorkflowRunsClient) ListWithQueryPreparer(resourceGroupName string, workflowName string, query ODataQuery) (*http.Request, error) {
+	pathParameters := map[string]interface{}{
+		"resourceGroupName": autorest.Encode("path", resourceGroupName),
+		"subscriptionId":    autorest.Encode("path", client.SubscriptionID),
+		"workflowName":      autorest.Encode("path", workflowName),
+	}
+
+	queryParameters := map[string]interface{}{
+		"api-version": client.APIVersion,
+	}
+	if query.Top != nil {
+		queryParameters["$top"] = autorest.Encode("query", *query.Top)
+	}
+	if query.Skip != nil {
+		queryParameters["$skip"] = autorest.Encode("query", *query.Skip)
+	}
+	if len(query.Filter) > 0 {
+		if err := ValidateFilter(query.Filter); err != nil {
+			return nil, err
+		}
+		queryParameters["$filter"] = autorest.Encode("query", query.Filter)
+	}
+	if len(query.OrderBy) > 0 {
+		queryParameters["$orderby"] = autorest.Encode("query", query.OrderBy)
+	}
+
+	preparer := autorest.CreatePreparer(
+		autorest.AsGet(),
+		autorest.WithBaseURL(client.BaseURI),
+		autorest.WithPathParameters("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Logic/workflows/{workflowName}/runs", pathParameters),
+		autorest.WithQueryParameters(queryParameters))
+	return preparer.Prepare(&http.Request{})
+}
@@ -0,0 +1,33 @@
+package logic
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestListWithQueryPreparer(t *testing.T) {
+	client := NewWorkflowRunsClient("00000000-0000-0000-0000-000000000000")
+	top, skip := int32(10), int32(20)
+	req, err := client.ListWithQueryPreparer("rg", "wf", ODataQuery{Top: &top, Skip: &skip, OrderBy: "startTime desc"})
+	if err != nil {
+		t.Fatalf("ListWithQueryPreparer()=%v", err)
+	}
+
+	want := url.Values{
+		"api-version": {DefaultAPIVersion},
+		"$top":        {"10"},
+		"$skip":       {"20"},
+		"$orderby":    {"startTime desc"},
+	}
+	if got := req.URL.Query(); got.Encode() != want.Encode() {
+		t.Fatalf("got query %q, want %q", got.Encode(), want.Encode())
+	}
+}
+
+func TestListWithQueryNegativeSkip(t *testing.T) {
+	client := NewWorkflowRunsClient("00000000-0000-0000-0000-000000000000")
+	skip := int32(-1)
+	if _, err := client.ListWithQuery("rg", "wf", ODataQuery{Skip: &skip}); err == nil {
+		t.Fatal("ListWithQuery() with a negative $skip succeeded, want a validation error")
+	}
+}
@@ -0,0 +1,69 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// ScopedWorkflowRunsClient wraps a WorkflowRunsClient bound to a resource
+// group, so that it does not have to be passed to every call. Use
+// WorkflowRunsClient.WithResourceGroup to create one.
+type ScopedWorkflowRunsClient struct {
+	WorkflowRunsClient WorkflowRunsClient
+	ResourceGroupName  string
+}
+
+// WithResourceGroup returns a client for the runs of the workflows in the
+// given resource group.
+func (client WorkflowRunsClient) WithResourceGroup(resourceGroupName string) ScopedWorkflowRunsClient {
+	return ScopedWorkflowRunsClient{WorkflowRunsClient: client, ResourceGroupName: resourceGroupName}
+}
+
+// WithHeaders returns a copy of the client whose requests carry the given
+// extra headers. See WorkflowRunsClient.WithHeaders.
+func (client ScopedWorkflowRunsClient) WithHeaders(headers http.Header, override ...string) ScopedWorkflowRunsClient {
+	return ScopedWorkflowRunsClient{WorkflowRunsClient: client.WorkflowRunsClient.WithHeaders(headers, override...), ResourceGroupName: client.ResourceGroupName}
+}
+
+// ListNextResults retrieves the next set of results, if any. See
+// WorkflowRunsClient.ListNextResults.
+func (client ScopedWorkflowRunsClient) ListNextResults(lastResults WorkflowRunListResult) (result WorkflowRunListResult, err error) {
+	return client.WorkflowRunsClient.ListNextResults(lastResults)
+}
+
+// Get gets a workflow run. See WorkflowRunsClient.Get.
+func (client ScopedWorkflowRunsClient) Get(workflowName string, runName string) (result WorkflowRun, err error) {
+	return client.WorkflowRunsClient.Get(client.ResourceGroupName, workflowName, runName)
+}
+
+// List gets a list of workflow runs. See WorkflowRunsClient.List; use
+// ListNextResults to page through the results.
+func (client ScopedWorkflowRunsClient) List(workflowName string, top *int32, filter string) (result WorkflowRunListResult, err error) {
+	return client.WorkflowRunsClient.List(client.ResourceGroupName, workflowName, top, filter)
+}
+
+// ListWithQuery gets a list of workflow runs with the given OData query
+// options. See WorkflowRunsClient.ListWithQuery.
+func (client ScopedWorkflowRunsClient) ListWithQuery(workflowName string, query ODataQuery) (result WorkflowRunListResult, err error) {
+	return client.WorkflowRunsClient.ListWithQuery(client.ResourceGroupName, workflowName, query)
+}
+
+// Cancel cancels a workflow run. See WorkflowRunsClient.Cancel.
+func (client ScopedWorkflowRunsClient) Cancel(workflowName string, runName string) (result autorest.Response, err error) {
+	return client.WorkflowRunsClient.Cancel(client.ResourceGroupName, workflowName, runName)
+}
@@ -0,0 +1,39 @@
+package logic
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScopedWorkflowRunsClient(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		fmt.Fprint(w, `{}`)
+	}))
+	defer srv.Close()
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription").WithResourceGroup("group")
+
+	const workflow = "/subscriptions/subscription/resourceGroups/group/providers/Microsoft.Logic/workflows/workflow"
+	testCases := []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{"Get", func() error { _, err := client.Get("workflow", "run"); return err }, workflow + "/runs/run"},
+		{"List", func() error { _, err := client.List("workflow", nil, ""); return err }, workflow + "/runs"},
+		{"ListWithQuery", func() error { _, err := client.ListWithQuery("workflow", ODataQuery{}); return err }, workflow + "/runs"},
+		{"Cancel", func() error { _, err := client.Cancel("workflow", "run"); return err }, workflow + "/runs/run/cancel"},
+	}
+	for _, testCase := range testCases {
+		path = ""
+		if err := testCase.call(); err != nil {
+			t.Fatalf("%s()=%v", testCase.name, err)
+		}
+		if path != testCase.want {
+			t.Errorf("%s(): got path %q, want %q", testCase.name, path, testCase.want)
+		}
+	}
+}
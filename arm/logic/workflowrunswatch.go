@@ -0,0 +1,134 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// DefaultWatchInterval is the polling interval Watch uses when given a
+// non-positive one.
+const DefaultWatchInterval = 10 * time.Second
+
+// Watch polls a workflow run every interval and sends it on the returned
+// run channel whenever its status changes, starting with the first status
+// seen. Both channels are closed once the run reaches a terminal status, ctx
+// is done or a Get fails, in which case the error is sent on the error
+// channel first. The Gets are sent with ctx, so the polling goroutine exits
+// as soon as ctx is done, aborting an in-flight Get, even if nobody receives
+// from the channels anymore.
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name. runName is the workflow run name.
+func (client WorkflowRunsClient) Watch(ctx context.Context, resourceGroupName string, workflowName string, runName string, interval time.Duration) (<-chan WorkflowRun, <-chan error) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	runs := make(chan WorkflowRun)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(runs)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var last WorkflowStatus
+		for first := true; ; first = false {
+			run, err := client.getWithContext(ctx, resourceGroupName, workflowName, runName)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			var status WorkflowStatus
+			if run.WorkflowRunProperties != nil {
+				status = run.WorkflowRunProperties.Status
+			}
+			if first || status != last {
+				last = status
+				select {
+				case runs <- run:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if status.IsTerminal() {
+				return
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return runs, errs
+}
+
+// getWithContext works like Get, but sends the request with ctx.
+func (client WorkflowRunsClient) getWithContext(ctx context.Context, resourceGroupName string, workflowName string, runName string) (result WorkflowRun, err error) {
+	req, err := client.GetPreparer(resourceGroupName, workflowName, runName)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowRunsClient", "Get", nil, "Failure preparing request")
+		return
+	}
+	req = req.WithContext(ctx)
+	if client.ETagCache != nil {
+		return client.getCached(req)
+	}
+
+	resp, err := client.GetSender(req)
+	if err != nil {
+		result.Response = autorest.Response{Response: resp}
+		err = autorest.NewErrorWithError(err, "logic.WorkflowRunsClient", "Get", resp, "Failure sending request: %s", describeRequest(req))
+		return
+	}
+
+	result, err = client.GetResponder(resp)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowRunsClient", "Get", resp, "Failure responding to request: %s", describeRequest(req))
+	}
+	return
+}
+
+// WaitForTerminal polls a workflow run every interval, DefaultWatchInterval
+// if not positive, until its status is terminal, and returns the run as last
+// received. If a Get fails or ctx is done first, the error, e.g. ctx.Err(),
+// is returned along with the last run received, if any.
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name. runName is the workflow run name.
+func (client WorkflowRunsClient) WaitForTerminal(ctx context.Context, resourceGroupName string, workflowName string, runName string, interval time.Duration) (WorkflowRun, error) {
+	runs, errs := client.Watch(ctx, resourceGroupName, workflowName, runName, interval)
+	var last WorkflowRun
+	for run := range runs {
+		last = run
+	}
+	if err := <-errs; err != nil {
+		return last, err
+	}
+	if last.WorkflowRunProperties != nil && last.Status.IsTerminal() {
+		return last, nil
+	}
+	return last, ctx.Err()
+}
@@ -0,0 +1,132 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	statuses := []string{"Waiting", "Running", "Running", "Running", "Succeeded", "Failed"}
+	var polls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"name":"run","properties":{"status":%q}}`, statuses[polls])
+		polls++
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+	runs, errs := client.Watch(context.Background(), "group", "workflow", "run", time.Millisecond)
+	var got []WorkflowStatus
+	for run := range runs {
+		got = append(got, run.Status)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if want := []WorkflowStatus{WorkflowStatusWaiting, WorkflowStatusRunning, WorkflowStatusSucceeded}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got statuses %v, want %v", got, want)
+	}
+	if polls != 5 {
+		t.Fatalf("got %d polls, want 5", polls)
+	}
+}
+
+func TestWatchCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"run","properties":{"status":"Running"}}`)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+	runs, errs := client.Watch(ctx, "group", "workflow", "run", time.Millisecond)
+	<-runs
+	cancel()
+
+	// Both channels are closed without any receiver having to drain them.
+	select {
+	case _, ok := <-errs:
+		if ok {
+			t.Fatal("got an error after cancellation, want the channel closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the watch did not stop after cancellation")
+	}
+	if _, ok := <-runs; ok {
+		t.Fatal("got a run after cancellation, want the channel closed")
+	}
+}
+
+func TestWatchCancelInFlight(t *testing.T) {
+	aborted := make(chan struct{})
+	var polls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls == 1 {
+			fmt.Fprint(w, `{"name":"run","properties":{"status":"Running"}}`)
+			return
+		}
+		// Hang until the request is aborted by the cancellation.
+		select {
+		case <-r.Context().Done():
+			close(aborted)
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+	runs, errs := client.Watch(ctx, "group", "workflow", "run", time.Millisecond)
+	<-runs
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-errs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the watch did not stop while a Get was in flight")
+	}
+	select {
+	case <-aborted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the in-flight Get was not aborted")
+	}
+}
+
+func TestWaitForTerminal(t *testing.T) {
+	statuses := []string{"Waiting", "Running", "Failed"}
+	var polls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"name":"run","properties":{"status":%q}}`, statuses[polls])
+		polls++
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowRunsClientWithBaseURI(srv.URL, "subscription")
+	run, err := client.WaitForTerminal(context.Background(), "group", "workflow", "run", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != WorkflowStatusFailed {
+		t.Fatalf("got status %v, want %v", run.Status, WorkflowStatusFailed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"run","properties":{"status":"Running"}}`)
+	})
+	run, err = client.WaitForTerminal(ctx, "group", "workflow", "run", time.Millisecond)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("WaitForTerminal()=%v, want %v", err, context.DeadlineExceeded)
+	}
+	if run.Status != WorkflowStatusRunning {
+		t.Fatalf("got status %v, want the last one seen", run.Status)
+	}
+}
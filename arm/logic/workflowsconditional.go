@@ -0,0 +1,127 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// ErrPreconditionFailed is returned by the conditional operations when the
+// resource has been modified since its ETag was read, i.e. when the service
+// responds with 412 Precondition Failed.
+var ErrPreconditionFailed = errors.New("logic: precondition failed, the resource has been modified")
+
+// ETag returns the entity tag of the workflow as reported in the response
+// it was read from, or an empty string if the response carried none. Pass
+// it to CreateOrUpdateIfMatch or DeleteIfMatch to make sure the workflow has
+// not been modified in the meantime.
+func (r Workflow) ETag() string {
+	if r.Response.Response == nil {
+		return ""
+	}
+	return r.Response.Header.Get("ETag")
+}
+
+// CreateOrUpdateIfMatch works like CreateOrUpdate, but only updates the
+// workflow if its current entity tag matches etag, which prevents lost
+// updates when several writers modify the same workflow. The special value
+// "*" matches any existing workflow. ErrPreconditionFailed is returned if
+// the workflow has been modified since etag was read.
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name. workflow is the workflow. etag is the expected entity tag.
+func (client WorkflowsClient) CreateOrUpdateIfMatch(resourceGroupName string, workflowName string, workflow Workflow, etag string) (result Workflow, err error) {
+	req, err := client.CreateOrUpdatePreparer(resourceGroupName, workflowName, workflow)
+	if err == nil {
+		req, err = withIfMatch(req, etag)
+	}
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowsClient", "CreateOrUpdateIfMatch", nil, "Failure preparing request")
+		return
+	}
+
+	resp, err := client.CreateOrUpdateSender(req)
+	if err != nil {
+		result.Response = autorest.Response{Response: resp}
+		err = autorest.NewErrorWithError(err, "logic.WorkflowsClient", "CreateOrUpdateIfMatch", resp, "Failure sending request: %s", describeRequest(req))
+		return
+	}
+	if isPreconditionFailed(resp) {
+		result.Response = autorest.Response{Response: resp}
+		return result, ErrPreconditionFailed
+	}
+
+	result, err = client.CreateOrUpdateResponder(resp)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowsClient", "CreateOrUpdateIfMatch", resp, "Failure responding to request: %s", describeRequest(req))
+	}
+
+	return
+}
+
+// DeleteIfMatch works like Delete, but only deletes the workflow if its
+// current entity tag matches etag. ErrPreconditionFailed is returned if the
+// workflow has been modified since etag was read.
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name. etag is the expected entity tag.
+func (client WorkflowsClient) DeleteIfMatch(resourceGroupName string, workflowName string, etag string) (result autorest.Response, err error) {
+	req, err := client.DeletePreparer(resourceGroupName, workflowName)
+	if err == nil {
+		req, err = withIfMatch(req, etag)
+	}
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowsClient", "DeleteIfMatch", nil, "Failure preparing request")
+		return
+	}
+
+	resp, err := client.DeleteSender(req)
+	if err != nil {
+		result.Response = resp
+		err = autorest.NewErrorWithError(err, "logic.WorkflowsClient", "DeleteIfMatch", resp, "Failure sending request: %s", describeRequest(req))
+		return
+	}
+	if isPreconditionFailed(resp) {
+		result.Response = resp
+		return result, ErrPreconditionFailed
+	}
+
+	result, err = client.DeleteResponder(resp)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowsClient", "DeleteIfMatch", resp, "Failure responding to request: %s", describeRequest(req))
+	}
+
+	return
+}
+
+func withIfMatch(req *http.Request, etag string) (*http.Request, error) {
+	if etag == "" {
+		return nil, errors.New("logic: etag required")
+	}
+	return autorest.Prepare(req, autorest.WithHeader("If-Match", etag))
+}
+
+// isPreconditionFailed reports whether the response is a 412 Precondition
+// Failed, in which case its body is closed.
+func isPreconditionFailed(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusPreconditionFailed {
+		return false
+	}
+	autorest.Respond(resp, autorest.ByDiscardingBody(), autorest.ByClosing())
+	return true
+}
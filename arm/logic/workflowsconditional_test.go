@@ -0,0 +1,47 @@
+package logic
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateOrUpdateIfMatch(t *testing.T) {
+	const etag = `"0800a7c1-0000-0000-0000-5a1b2c3d0000"`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("ETag", etag)
+			w.Write([]byte(`{"name":"workflow"}`))
+		case http.MethodPut, http.MethodDelete:
+			if r.Header.Get("If-Match") != etag {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			w.Write([]byte(`{"name":"workflow"}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowsClientWithBaseURI(srv.URL, "subscription")
+	workflow, err := client.Get("group", "workflow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := workflow.ETag(); got != etag {
+		t.Fatalf("ETag()=%q, want %q", got, etag)
+	}
+
+	if _, err := client.CreateOrUpdateIfMatch("group", "workflow", workflow, workflow.ETag()); err != nil {
+		t.Fatalf("CreateOrUpdateIfMatch()=%v", err)
+	}
+	if _, err := client.CreateOrUpdateIfMatch("group", "workflow", workflow, `"stale"`); err != ErrPreconditionFailed {
+		t.Fatalf("got error %v, want %v", err, ErrPreconditionFailed)
+	}
+	if _, err := client.DeleteIfMatch("group", "workflow", `"stale"`); err != ErrPreconditionFailed {
+		t.Fatalf("got error %v, want %v", err, ErrPreconditionFailed)
+	}
+	if _, err := client.DeleteIfMatch("group", "workflow", etag); err != nil {
+		t.Fatalf("DeleteIfMatch()=%v", err)
+	}
+}
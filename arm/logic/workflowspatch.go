@@ -0,0 +1,111 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// MergePatchContentType is the media type of the JSON merge patches sent by
+// WorkflowsClient.MergePatch, see RFC 7396.
+const MergePatchContentType = "application/merge-patch+json"
+
+// MergePatch partially updates a workflow with a JSON merge patch, modifying
+// only the fields present in patch, e.g. to disable a workflow without
+// sending its whole definition again:
+//
+//	client.MergePatch(group, name, map[string]interface{}{
+//		"properties": map[string]interface{}{"state": logic.WorkflowStateDisabled},
+//	})
+//
+// The nested objects of patch are merged into those of the workflow, and a
+// nil value removes the field. patch must not be empty.
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name. patch is the fields to modify.
+func (client WorkflowsClient) MergePatch(resourceGroupName string, workflowName string, patch map[string]interface{}) (result Workflow, err error) {
+	req, err := client.MergePatchPreparer(resourceGroupName, workflowName, patch)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowsClient", "MergePatch", nil, "Failure preparing request")
+		return
+	}
+
+	resp, err := client.MergePatchSender(req)
+	if err != nil {
+		result.Response = autorest.Response{Response: resp}
+		err = autorest.NewErrorWithError(err, "logic.WorkflowsClient", "MergePatch", resp, "Failure sending request: %s", describeRequest(req))
+		return
+	}
+
+	result, err = client.MergePatchResponder(resp)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowsClient", "MergePatch", resp, "Failure responding to request: %s", describeRequest(req))
+	}
+
+	return
+}
+
+// MergePatchPreparer prepares the MergePatch request.
+func (client WorkflowsClient) MergePatchPreparer(resourceGroupName string, workflowName string, patch map[string]interface{}) (*http.Request, error) {
+	if len(patch) == 0 {
+		return nil, errors.New("logic: empty merge patch")
+	}
+	// Unlike autorest.WithJSON, report the values which cannot be encoded.
+	body, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	pathParameters := map[string]interface{}{
+		"resourceGroupName": autorest.Encode("path", resourceGroupName),
+		"subscriptionId":    autorest.Encode("path", client.SubscriptionID),
+		"workflowName":      autorest.Encode("path", workflowName),
+	}
+
+	queryParameters := map[string]interface{}{
+		"api-version": client.APIVersion,
+	}
+
+	preparer := autorest.CreatePreparer(
+		autorest.AsContentType(MergePatchContentType),
+		autorest.AsPatch(),
+		autorest.WithBaseURL(client.BaseURI),
+		autorest.WithPathParameters("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Logic/workflows/{workflowName}", pathParameters),
+		autorest.WithString(string(body)),
+		autorest.WithQueryParameters(queryParameters))
+	return preparer.Prepare(&http.Request{})
+}
+
+// MergePatchSender sends the MergePatch request. The method will close the
+// http.Response Body if it receives an error.
+func (client WorkflowsClient) MergePatchSender(req *http.Request) (*http.Response, error) {
+	return autorest.SendWithSender(client, req)
+}
+
+// MergePatchResponder handles the response to the MergePatch request. The
+// method always closes the http.Response Body.
+func (client WorkflowsClient) MergePatchResponder(resp *http.Response) (result Workflow, err error) {
+	err = autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		withErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByUnmarshallingJSON(&result),
+		autorest.ByClosing())
+	result.Response = autorest.Response{Response: resp}
+	return
+}
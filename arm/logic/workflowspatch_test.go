@@ -0,0 +1,44 @@
+package logic
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		if r.Method != http.MethodPatch || r.Header.Get("Content-Type") != MergePatchContentType {
+			t.Errorf("got %s with Content-Type %q, want a merge patch", r.Method, r.Header.Get("Content-Type"))
+		}
+		if want := `{"properties":{"state":"Disabled"},"tags":{"owner":null}}`; string(body) != want {
+			t.Errorf("got body %s, want %s", body, want)
+		}
+		w.Write([]byte(`{"name":"workflow","properties":{"state":"Disabled"}}`))
+	}))
+	defer srv.Close()
+
+	client := NewWorkflowsClientWithBaseURI(srv.URL, "subscription")
+	workflow, err := client.MergePatch("group", "workflow", map[string]interface{}{
+		"properties": map[string]interface{}{"state": WorkflowStateDisabled},
+		"tags":       map[string]interface{}{"owner": nil},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if workflow.WorkflowProperties == nil || workflow.State != WorkflowStateDisabled {
+		t.Fatalf("got workflow %+v, want it disabled", workflow)
+	}
+
+	if _, err := client.MergePatch("group", "workflow", nil); err == nil {
+		t.Fatal("MergePatch() with an empty patch succeeded")
+	}
+	if _, err := client.MergePatch("group", "workflow", map[string]interface{}{"properties": make(chan int)}); err == nil {
+		t.Fatal("MergePatch() with a patch which cannot be encoded succeeded")
+	}
+}
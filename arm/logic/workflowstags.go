@@ -0,0 +1,99 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import (
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// tagsPatch is the body of UpdateTags. Unlike in Workflow, the tags are not
+// omitted when empty, and a nil value is sent as null.
+type tagsPatch struct {
+	Tags map[string]*string `json:"tags"`
+}
+
+// UpdateTags updates the resource tags of a workflow, leaving the rest of
+// it untouched. The tags are merged into the existing ones as per the ARM
+// PATCH semantics: a tag with a nil value is removed from the workflow, and
+// the tags not listed are kept.
+//
+// resourceGroupName is the resource group name. workflowName is the workflow
+// name. tags is the tags to set or, with a nil value, remove.
+func (client WorkflowsClient) UpdateTags(resourceGroupName string, workflowName string, tags map[string]*string) (result Workflow, err error) {
+	req, err := client.UpdateTagsPreparer(resourceGroupName, workflowName, tags)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowsClient", "UpdateTags", nil, "Failure preparing request")
+		return
+	}
+
+	resp, err := client.UpdateTagsSender(req)
+	if err != nil {
+		result.Response = autorest.Response{Response: resp}
+		err = autorest.NewErrorWithError(err, "logic.WorkflowsClient", "UpdateTags", resp, "Failure sending request: %s", describeRequest(req))
+		return
+	}
+
+	result, err = client.UpdateTagsResponder(resp)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "logic.WorkflowsClient", "UpdateTags", resp, "Failure responding to request: %s", describeRequest(req))
+	}
+
+	return
+}
+
+// UpdateTagsPreparer prepares the UpdateTags request.
+func (client WorkflowsClient) UpdateTagsPreparer(resourceGroupName string, workflowName string, tags map[string]*string) (*http.Request, error) {
+	pathParameters := map[string]interface{}{
+		"resourceGroupName": autorest.Encode("path", resourceGroupName),
+		"subscriptionId":    autorest.Encode("path", client.SubscriptionID),
+		"workflowName":      autorest.Encode("path", workflowName),
+	}
+
+	queryParameters := map[string]interface{}{
+		"api-version": client.APIVersion,
+	}
+
+	if tags == nil {
+		tags = map[string]*string{}
+	}
+	preparer := autorest.CreatePreparer(
+		autorest.AsJSON(),
+		autorest.AsPatch(),
+		autorest.WithBaseURL(client.BaseURI),
+		autorest.WithPathParameters("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Logic/workflows/{workflowName}", pathParameters),
+		autorest.WithJSON(tagsPatch{Tags: tags}),
+		autorest.WithQueryParameters(queryParameters))
+	return preparer.Prepare(&http.Request{})
+}
+
+// UpdateTagsSender sends the UpdateTags request. The method will close the
+// http.Response Body if it receives an error.
+func (client WorkflowsClient) UpdateTagsSender(req *http.Request) (*http.Response, error) {
+	return autorest.SendWithSender(client, req)
+}
+
+// UpdateTagsResponder handles the response to the UpdateTags request. The
+// method always closes the http.Response Body.
+func (client WorkflowsClient) UpdateTagsResponder(resp *http.Response) (result Workflow, err error) {
+	err = autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		withErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByUnmarshallingJSON(&result),
+		autorest.ByClosing())
+	result.Response = autorest.Response{Response: resp}
+	return
+}
@@ -0,0 +1,97 @@
+package logic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+// tagsServer serves a single workflow, applying the tags of the PUT and
+// PATCH requests to it the way ARM does.
+func tagsServer(t *testing.T) *httptest.Server {
+	tags := map[string]string{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		var req struct {
+			Tags map[string]*string `json:"tags"`
+		}
+		if len(body) != 0 {
+			if err := json.Unmarshal(body, &req); err != nil {
+				t.Errorf("%s: %v", r.Method, err)
+			}
+		}
+		switch r.Method {
+		case http.MethodPut:
+			tags = map[string]string{}
+			fallthrough
+		case http.MethodPatch:
+			for k, v := range req.Tags {
+				if v == nil {
+					delete(tags, k)
+				} else {
+					tags[k] = *v
+				}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "workflow", "tags": tags})
+	}))
+}
+
+func TestUpdateTags(t *testing.T) {
+	srv := tagsServer(t)
+	defer srv.Close()
+	client := NewWorkflowsClientWithBaseURI(srv.URL, "subscription")
+
+	workflow := Workflow{Tags: &map[string]*string{
+		"costCenter": to.StringPtr("42"),
+		"owner":      to.StringPtr("ops"),
+	}}
+	got, err := client.CreateOrUpdate("group", "workflow", workflow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Tags == nil || !reflect.DeepEqual(*got.Tags, *workflow.Tags) {
+		t.Fatalf("CreateOrUpdate() tags: got %v, want %v", got.Tags, *workflow.Tags)
+	}
+
+	got, err = client.UpdateTags("group", "workflow", map[string]*string{
+		"owner": nil,
+		"env":   to.StringPtr("prod"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*string{
+		"costCenter": to.StringPtr("42"),
+		"env":        to.StringPtr("prod"),
+	}
+	if got.Tags == nil || !reflect.DeepEqual(*got.Tags, want) {
+		t.Fatalf("UpdateTags() tags: got %v, want %v", got.Tags, want)
+	}
+}
+
+func TestUpdateTagsPreparer(t *testing.T) {
+	client := NewWorkflowsClient("subscription")
+	req, err := client.UpdateTagsPreparer("group", "workflow", map[string]*string{"owner": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Method != http.MethodPatch {
+		t.Fatalf("got method %s, want %s", req.Method, http.MethodPatch)
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"tags":{"owner":null}}`; string(body) != want {
+		t.Fatalf("got body %s, want %s", body, want)
+	}
+}
@@ -0,0 +1,36 @@
+package logic
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Workflow returns a workflow carrying the definition of the version, which
+// can be passed to WorkflowsClient.CreateOrUpdate to roll the workflow back
+// to it. Only the writable properties are copied; the read-only ones, such
+// as the creation time or the version itself, are assigned by the service.
+func (v WorkflowVersion) Workflow() Workflow {
+	w := Workflow{
+		Location: v.Location,
+		Tags:     v.Tags,
+	}
+	if p := v.WorkflowVersionProperties; p != nil {
+		w.WorkflowProperties = &WorkflowProperties{
+			State:              p.State,
+			Sku:                p.Sku,
+			IntegrationAccount: p.IntegrationAccount,
+			Definition:         p.Definition,
+			Parameters:         p.Parameters,
+		}
+	}
+	return w
+}
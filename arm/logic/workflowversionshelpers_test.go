@@ -0,0 +1,63 @@
+package logic
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWorkflowVersionWorkflow(t *testing.T) {
+	const version = `{
+		"id": "/subscriptions/subscription/resourceGroups/group/providers/Microsoft.Logic/workflows/workflow/versions/1",
+		"name": "1",
+		"type": "Microsoft.Logic/workflows/versions",
+		"location": "westus",
+		"tags": {"env": "prod"},
+		"properties": {
+			"createdTime": "2017-05-01T12:00:00Z",
+			"changedTime": "2017-05-02T12:00:00Z",
+			"state": "Disabled",
+			"version": "1",
+			"accessEndpoint": "https://prod.westus.logic.azure.com/workflows/1",
+			"sku": {"name": "Standard", "plan": {"id": "plan"}},
+			"integrationAccount": {"id": "account"},
+			"definition": {"triggers": {"manual": {"type": "Request"}}},
+			"parameters": {"settings": {"type": "Object", "value": {"retries": 3}}}
+		}
+	}`
+	var v WorkflowVersion
+	if err := json.Unmarshal([]byte(version), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(v.Workflow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotJSON, wantJSON interface{}
+	want := `{
+		"location": "westus",
+		"tags": {"env": "prod"},
+		"properties": {
+			"state": "Disabled",
+			"sku": {"name": "Standard", "plan": {"id": "plan"}},
+			"integrationAccount": {"id": "account"},
+			"definition": {"triggers": {"manual": {"type": "Request"}}},
+			"parameters": {"settings": {"type": "Object", "value": {"retries": 3}}}
+		}
+	}`
+	if err := json.Unmarshal(got, &gotJSON); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantJSON); err != nil {
+		t.Fatal(err)
+	}
+	gotCanonical, _ := json.Marshal(gotJSON)
+	wantCanonical, _ := json.Marshal(wantJSON)
+	if string(gotCanonical) != string(wantCanonical) {
+		t.Fatalf("got workflow %s, want %s", gotCanonical, wantCanonical)
+	}
+
+	if w := (WorkflowVersion{}).Workflow(); w.WorkflowProperties != nil {
+		t.Fatalf("got properties %+v for a version without any", *w.WorkflowProperties)
+	}
+}
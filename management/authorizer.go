@@ -0,0 +1,44 @@
+package management
+
+import (
+	"net/http"
+)
+
+// Authorizer authenticates the requests sent to the management API, as an
+// alternative to a management certificate. It is set through
+// ClientConfig.Authorizer.
+//
+// The Service Management API (https://management.core.windows.net) accepts
+// either a management certificate or an Azure Active Directory bearer token
+// issued for it, while Azure Resource Manager endpoints, such as those used by
+// the arm packages, only accept bearer tokens. An Authorizer makes it possible
+// to use the same token-based credentials for both.
+type Authorizer interface {
+	// Authorize adds the credentials, e.g. an Authorization header, to the
+	// request. It is called for every attempt at sending a request, so it
+	// may refresh expired tokens.
+	Authorize(req *http.Request) error
+}
+
+// AuthorizerFunc is an adapter allowing the use of an ordinary function as
+// an Authorizer. For instance, an autorest.Authorizer can be used with:
+//
+//	management.AuthorizerFunc(func(req *http.Request) error {
+//		_, err := autorest.Prepare(req, authorizer.WithAuthorization())
+//		return err
+//	})
+type AuthorizerFunc func(req *http.Request) error
+
+// Authorize calls f(req).
+func (f AuthorizerFunc) Authorize(req *http.Request) error {
+	return f(req)
+}
+
+// BearerToken returns an Authorizer which sets a fixed bearer token in the
+// Authorization header of the requests.
+func BearerToken(token string) Authorizer {
+	return AuthorizerFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
@@ -0,0 +1,87 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrCancelNotSupported is returned by ClientConfig.CancelOperation for an
+// operation which cannot be cancelled.
+var ErrCancelNotSupported = errors.New("azure: the operation does not support cancellation")
+
+// CancelResult reports the outcome of CancelAllOperations for each of the
+// operations being polled, sorted by ID.
+type CancelResult struct {
+	// Cancelled are the operations which were cancelled.
+	Cancelled []OperationID
+
+	// Completed are the operations which had already completed, either
+	// successfully or not, or which the API has forgotten.
+	Completed []OperationID
+
+	// Stopped are the operations which do not support cancellation, or
+	// failed to be cancelled: their polls were stopped, but the operations
+	// may still be running.
+	Stopped []OperationID
+}
+
+func (c client) CancelAllOperations(ctx context.Context) (CancelResult, error) {
+	var result CancelResult
+	polls := c.trackedPolls()
+	if len(polls) == 0 {
+		return result, nil
+	}
+	ids := make([]OperationID, 0, len(polls))
+	for id := range polls {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var errs []error
+	for _, id := range ids {
+		op, err := c.getOperationStatus(ctx, id)
+		if err == ErrOperationNotFound || err == nil && op.Status.IsTerminal() {
+			result.Completed = append(result.Completed, id)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("azure: getting the status of operation %s: %w", id, err))
+		} else if err = c.cancelOperation(ctx, id); err == nil {
+			result.Cancelled = append(result.Cancelled, id)
+		} else if !errors.Is(err, ErrCancelNotSupported) {
+			errs = append(errs, fmt.Errorf("azure: cancelling operation %s: %w", id, err))
+		}
+		if err != nil {
+			result.Stopped = append(result.Stopped, id)
+		}
+		for _, poll := range polls[id] {
+			poll.cancel(ErrOperationCancelled)
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// cancelOperation cancels the given operation through the CancelOperation
+// of the configuration.
+func (c client) cancelOperation(ctx context.Context, operationID OperationID) error {
+	if c.config.CancelOperation == nil {
+		return ErrCancelNotSupported
+	}
+	return c.config.CancelOperation(ctx, c, operationID)
+}
+
+// trackedPolls returns the polls in progress by operation.
+func (c client) trackedPolls() map[OperationID][]*trackedPoll {
+	if c.state == nil {
+		return nil
+	}
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	polls := make(map[OperationID][]*trackedPoll)
+	for poll := range c.state.polls {
+		polls[poll.operationID] = append(polls[poll.operationID], poll)
+	}
+	return polls
+}
@@ -3,9 +3,19 @@
 package management
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
 	"runtime"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -14,9 +24,22 @@ var (
 )
 
 const (
-	DefaultAzureManagementURL    = "https://management.core.windows.net"
-	DefaultOperationPollInterval = time.Second * 30
-	DefaultAPIVersion            = "2014-10-01"
+	DefaultAzureManagementURL     = "https://management.core.windows.net"
+	DefaultOperationPollInterval  = time.Second * 30
+	DefaultOperationPollMaxErrors = 3
+	DefaultAPIVersion             = "2014-10-01"
+	DefaultRetryBackoff           = time.Second
+	DefaultDialTimeout            = time.Second * 30
+	DefaultTLSHandshakeTimeout    = time.Second * 10
+	DefaultResponseHeaderTimeout  = time.Minute
+	DefaultMaxIdleConns           = 100
+	DefaultMaxIdleConnsPerHost    = 64
+	DefaultIdleConnTimeout        = 90 * time.Second
+
+	// EnvManagementURL and EnvAPIVersion are the environment variables read
+	// by ClientConfig.FromEnvironment.
+	EnvManagementURL = "AZURE_MANAGEMENT_URL"
+	EnvAPIVersion    = "AZURE_API_VERSION"
 
 	errPublishSettingsConfiguration       = "PublishSettingsFilePath is set. Consequently ManagementCertificatePath and SubscriptionId must not be set."
 	errManagementCertificateConfiguration = "Both ManagementCertificatePath and SubscriptionId should be set, and PublishSettingsFilePath must not be set."
@@ -26,6 +49,32 @@ const (
 type client struct {
 	publishSettings publishSettings
 	config          ClientConfig
+	httpClient      *http.Client
+	state           *clientState
+}
+
+// clientState is the mutable state shared by all the copies of a client.
+type clientState struct {
+	mu           sync.Mutex
+	rateLimit    RateLimit
+	hasRateLimit bool
+
+	// serverTime is the Date of the most recent response and received
+	// the local time at which that response arrived.
+	serverTime time.Time
+	received   time.Time
+
+	// closed is set by Shutdown, which closes shutdown to stop the operation
+	// polls tracked by pollers.
+	closed   bool
+	shutdown chan struct{}
+	pollers  sync.WaitGroup
+
+	// polls are the operation polls in progress, for CancelAllOperations.
+	polls map[*trackedPoll]struct{}
+
+	// retries are the retries spent from the RetryBudget, oldest first.
+	retries []budgetedRetry
 }
 
 // Client is the base Azure Service Management API client instance that
@@ -36,9 +85,24 @@ type Client interface {
 	SendAzureGetRequest(url string) ([]byte, error)
 
 	// SendAzurePostRequest sends a request to the management API using the HTTP POST method
-	// and returns the request ID or an error.
+	// and returns the request ID or an error. ErrCompletedSynchronously is
+	// returned if the API completed the request without starting an operation.
 	SendAzurePostRequest(url string, data []byte) (OperationID, error)
 
+	// SendAzurePostRequestAsync sends a request to the management API using
+	// the HTTP POST method and returns the headers describing the started
+	// operation, whichever convention the API follows. Pass its ID to
+	// WaitForOperation or GetOperationStatus to wait for it.
+	SendAzurePostRequestAsync(url string, data []byte) (AsyncOperation, error)
+
+	// SendAzurePostRequestExpect is like SendAzurePostRequest but, if any
+	// accepted status codes are given, fails with an AzureError unless the
+	// response has one of them, even a successful one, and succeeds on an
+	// accepted error status, which is not retried. The request ID is empty
+	// if the response does not carry one, e.g. when the request completed
+	// synchronously.
+	SendAzurePostRequestExpect(url string, data []byte, accepted ...int) (OperationID, error)
+
 	// SendAzurePostRequestWithReturnedResponse sends a request to the management API using
 	// the HTTP POST method and returns the response body or an error.
 	SendAzurePostRequestWithReturnedResponse(url string, data []byte) ([]byte, error)
@@ -46,16 +110,61 @@ type Client interface {
 	// SendAzurePutRequest sends a request to the management API using the HTTP PUT method
 	// and returns the request ID or an error. The content type can be specified, however
 	// if an empty string is passed, the default of "application/xml" will be used.
+	// ErrCompletedSynchronously is returned as by SendAzurePostRequest.
 	SendAzurePutRequest(url, contentType string, data []byte) (OperationID, error)
 
+	// SendAzurePutRequestExpect is like SendAzurePutRequest, with the
+	// accepted status codes restricted as in SendAzurePostRequestExpect.
+	SendAzurePutRequestExpect(url, contentType string, data []byte, accepted ...int) (OperationID, error)
+
+	// SendAzurePutRequestGzip works like SendAzurePutRequest, but compresses
+	// the request body with gzip and sends it with a Content-Encoding: gzip
+	// header. Use it only with the endpoints which decode compressed request
+	// bodies; an error wrapping the AzureError is returned if the endpoint
+	// rejects the encoding with 415 Unsupported Media Type.
+	SendAzurePutRequestGzip(url, contentType string, data []byte) (OperationID, error)
+
+	// SendAzurePutRequestStream works like SendAzurePutRequest, but streams the
+	// request body from the given reader instead of buffering it in memory.
+	// The length is sent as the Content-Length of the request. The request is
+	// retried on failure only if body is an io.Seeker, so it can be rewound.
+	SendAzurePutRequestStream(url, contentType string, body io.Reader, length int64) (OperationID, error)
+
+	// SendAzurePutRequestStreamFunc works like SendAzurePutRequestStream, but
+	// the body is obtained by calling getBody, once for each attempt, which
+	// allows retrying the request with any kind of reader.
+	SendAzurePutRequestStreamFunc(url, contentType string, getBody func() (io.ReadCloser, error), length int64) (OperationID, error)
+
 	// SendAzureDeleteRequest sends a request to the management API using the HTTP DELETE method
-	// and returns the request ID or an error.
+	// and returns the request ID or an error. ErrCompletedSynchronously is
+	// returned as by SendAzurePostRequest.
 	SendAzureDeleteRequest(url string) (OperationID, error)
 
 	// GetOperationStatus gets the status of operation with given Operation ID.
 	// WaitForOperation utility method can be used for polling for operation status.
+	// ErrOperationNotFound is returned if the operation does not exist or has expired.
 	GetOperationStatus(operationID OperationID) (GetOperationStatusResponse, error)
 
+	// GetOperationStatusURL gets the status of an ARM-style asynchronous
+	// operation from the absolute status URL returned by the API in the
+	// Azure-AsyncOperation header. The send methods return that URL as the
+	// OperationID when the header is present, so WaitForOperation and
+	// GetOperationStatus work with either kind of operation; see OperationID.
+	GetOperationStatusURL(statusURL string) (GetOperationStatusResponse, error)
+
+	// IsOperationComplete fetches the status of the given operation once,
+	// without waiting for it, and reports whether it has reached a terminal
+	// state. A failed operation is reported as done along with its error;
+	// an error fetching the status is reported with done set to false.
+	IsOperationComplete(operationID OperationID) (done bool, err error)
+
+	// OperationStatusURL returns the URL GetOperationStatus reads the status
+	// of the operation from, e.g. to request it manually when debugging.
+	// The API version is not part of the URL: it is sent in the x-ms-version
+	// header, along with the credentials of the client. It returns an empty
+	// string for an empty operationID.
+	OperationStatusURL(operationID OperationID) string
+
 	// WaitForOperation polls the Azure API for given operation ID indefinitely
 	// until the operation is completed with either success or failure.
 	// It is meant to be used for waiting for the result of the methods that
@@ -67,8 +176,141 @@ type Client interface {
 	// method.
 	//
 	// If the operation was not successful or cancelling is signaled, an error
+	// is returned. If the API does not know the operation, ErrOperationNotFound
 	// is returned.
+	//
+	// The cancel channel is only consulted between two polls: a terminal
+	// status received from the API is always returned, even if cancelling is
+	// signaled meanwhile, and once the channel is closed no further poll is
+	// made, even if the next one is due at the same time.
 	WaitForOperation(operationID OperationID, cancel chan struct{}) error
+
+	// WaitForOperationProgress works like WaitForOperation, but additionally
+	// invokes onProgress after each poll with the percentage of the operation
+	// completed so far. The callback is not called when the API does not
+	// report progress for the operation.
+	WaitForOperationProgress(operationID OperationID, onProgress func(percent int), cancel chan struct{}) error
+
+	// WaitForOperationFunc works like WaitForOperation, but additionally
+	// invokes onPoll with the status received at each poll while the
+	// operation is not done, i.e. in progress or in a status reported as
+	// OperationStatusUnknown, e.g. to emit a heartbeat. onPoll is not called
+	// with the terminal status, which is reported by the returned error
+	// instead, nor after it.
+	WaitForOperationFunc(operationID OperationID, onPoll func(status GetOperationStatusResponse), cancel chan struct{}) error
+
+	// WaitForOperationUntil works like WaitForOperation, but additionally
+	// stops polling, returning nil, as soon as done reports true for the
+	// status received at a poll, e.g. once a resource reaches a state the
+	// caller considers good enough while the operation is still in progress.
+	// The terminal statuses still end the polling whatever done reports, a
+	// failure being returned as an error. A predicate which never becomes
+	// true makes it poll until the operation completes, so bound the wait
+	// with the cancel channel when in doubt.
+	WaitForOperationUntil(operationID OperationID, done func(status GetOperationStatusResponse) bool, cancel chan struct{}) error
+
+	// StartWaitForOperation starts polling for the status of the given
+	// operation in the background, like WaitForOperation does. The returned
+	// channel receives the result once the operation completes. Calling
+	// cancel stops the polling, aborting any in-flight status request, and
+	// delivers ErrOperationCancelled unless the result is already known.
+	// cancel may be called multiple times; the polling goroutine exits in
+	// either case.
+	StartWaitForOperation(operationID OperationID) (done <-chan error, cancel func())
+
+	// DeleteAndWait sends a request to the management API using the HTTP DELETE
+	// method and, if a long running operation was started, waits for it to
+	// complete. A resource that is already gone, i.e. a 404 Not Found
+	// response whatever its error code, is not treated as an error.
+	//
+	// Both sending the request and polling for the operation status are
+	// cancelled when ctx is done, in which case ctx.Err() is returned.
+	DeleteAndWait(ctx context.Context, url string) error
+
+	// PutAndWait sends a request to the management API using the HTTP PUT method
+	// and waits for the started operation to complete. See DeleteAndWait for
+	// details on cancellation.
+	PutAndWait(ctx context.Context, url, contentType string, data []byte) error
+
+	// PostAndWait sends a request to the management API using the HTTP POST method
+	// and waits for the started operation to complete. See DeleteAndWait for
+	// details on cancellation.
+	PostAndWait(ctx context.Context, url string, data []byte) error
+
+	// CreateOrUpdateAndWait sends a resource definition to the management API
+	// using the HTTP PUT method and waits for the started operation to
+	// complete. The in-flight request and the status polling are bound to
+	// ctx: once ctx is done the request is aborted, polling stops and
+	// ctx.Err() is returned, so it can be told apart from a failed
+	// operation.
+	CreateOrUpdateAndWait(ctx context.Context, url, contentType string, data []byte) error
+
+	// CreateOrUpdateWithRollback is like CreateOrUpdateAndWait but, if ctx
+	// is done before the operation completes, the resource may have been
+	// created nonetheless, so it makes a best-effort attempt at deleting it
+	// at url, waiting for the deletion for at most five minutes regardless
+	// of ctx. rolledBack reports whether the deletion succeeded, in which
+	// case err is ctx.Err(); if it failed, err wraps both ctx.Err() and the
+	// deletion error. The rollback is advisory: the API may still complete
+	// the creation after the deletion.
+	CreateOrUpdateWithRollback(ctx context.Context, url, contentType string, data []byte) (rolledBack bool, err error)
+
+	// LastRateLimit returns the throttling quotas reported by the most recent
+	// response which carried them. It returns false if no such response has
+	// been received yet.
+	LastRateLimit() (RateLimit, bool)
+
+	// ServerTime returns the time reported in the Date header of the most
+	// recent response which carried a valid one. It returns false if no
+	// such response has been received yet.
+	ServerTime() (time.Time, bool)
+
+	// ClockSkew estimates how far the server clock is ahead of the local
+	// clock, based on the Date header of the most recent response. A
+	// negative value means the local clock is ahead. Since the header has a
+	// resolution of one second, so does the estimate.
+	ClockSkew() (time.Duration, bool)
+
+	// Ping checks that the management API is reachable and accepts the
+	// credentials of the client, by reading the resource configured with
+	// ClientConfig.PingPath. It returns nil on success, an *AuthError if the
+	// credentials are rejected with 401 or 403, and a *ConnectivityError on
+	// any other failure, be it a transport error or another failure status.
+	// The request is sent like any other, following redirects and logged,
+	// but a failure is reported without being retried.
+	Ping(ctx context.Context) error
+
+	// WithManagementURL returns a copy of the client which sends its requests
+	// to the given management URL instead. The copy shares the credentials
+	// and the HTTP client with the original and both are safe for concurrent
+	// use. The throttling quotas and server time are tracked separately for
+	// each copy, as they are specific to the endpoint.
+	WithManagementURL(url string) Client
+
+	// Config returns a copy of the effective configuration of the client,
+	// i.e. with the defaults applied, e.g. to log the ManagementURL,
+	// APIVersion and UserAgent in use. Modifying the copy does not affect
+	// the client.
+	Config() ClientConfig
+
+	// Shutdown stops all the operation polls in progress, including those
+	// started with StartWaitForOperation, and waits for them to return or
+	// for ctx to be done, in which case it returns ctx.Err(). The stopped
+	// polls return ErrClientClosed. After Shutdown, the client rejects new
+	// requests with ErrClientClosed; the copies made by WithManagementURL are
+	// not affected and have to be shut down separately.
+	Shutdown(ctx context.Context) error
+
+	// CancelAllOperations aborts the operations whose status is being polled
+	// by the client, e.g. by WaitForOperation or StartWaitForOperation. For
+	// each operation still in progress, it issues the cancellation through
+	// ClientConfig.CancelOperation and stops the polls, which then return
+	// ErrOperationCancelled; the polls of the operations which have already
+	// completed are left to report their outcome. The result tells the
+	// operations apart; the returned error joins the failures to get the
+	// status of, or to cancel, an operation. It does nothing if no
+	// operation is being polled.
+	CancelAllOperations(ctx context.Context) (CancelResult, error)
 }
 
 // ClientConfig provides a configuration for use by a Client.
@@ -77,9 +319,185 @@ type ClientConfig struct {
 	OperationPollInterval time.Duration
 	UserAgent             string
 	APIVersion            string
+
+	// OperationPollMaxErrors is the number of consecutive transient failures,
+	// i.e. network or server-side errors, to get the status of an operation
+	// which the wait for the operation tolerates. Beyond it, or on any other
+	// failure, the wait fails. The retries are spaced by the poll interval
+	// plus an exponential backoff. Zero means the first failure is fatal.
+	OperationPollMaxErrors int
+
+	// CertificateChain holds the PEM-encoded intermediate CA certificates to
+	// present along with the management certificate, for the certificates
+	// not issued directly by a CA the endpoint trusts. They must be ordered
+	// from the issuer of the management certificate up; the intermediates
+	// found in the management certificate PEM itself, after the leaf, are
+	// presented first.
+	CertificateChain []byte
+
+	// RetryBackoff is the base delay between retries of a failed request.
+	// The delay grows exponentially with each attempt and is jittered.
+	// Zero means retrying immediately.
+	RetryBackoff time.Duration
+
+	// RetryPolicy decides which failed requests are retried. If nil,
+	// DefaultRetryPolicy limited to 5 retries is used.
+	RetryPolicy RetryPolicy
+
+	// RetryBudget, if set, bounds the retries of all the requests of the
+	// client, on top of the RetryPolicy of each. A client derived with
+	// WithManagementURL gets a budget of its own.
+	RetryBudget RetryBudget
+
+	// Rand returns a pseudo-random number in [0.0,1.0) used to jitter the
+	// retry backoff. It must be safe for concurrent use. If nil, a lock-free
+	// source private to the client and seeded at its construction is used,
+	// so that concurrent retries contend on neither the global math/rand
+	// source nor a mutex of their own. Tests can supply a deterministic
+	// function to make the backoff reproducible.
+	Rand func() float64
+
+	// HTTPClient, if set, is used to send all the requests instead of the
+	// client created internally. It must be configured to present the
+	// management certificate, and the transport settings below are ignored.
+	HTTPClient *http.Client
+
+	// DialTimeout, TLSHandshakeTimeout and ResponseHeaderTimeout configure
+	// the transport of the internally created HTTP client. They bound the
+	// time spent on connecting to the management API, completing the TLS
+	// handshake and waiting for the response headers respectively. Zero
+	// means no timeout.
+	DialTimeout           time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+
+	// ReadTimeout and WriteTimeout, if positive, bound the whole exchange,
+	// retries and reading the response body included, of the GET and HEAD
+	// requests and of the other requests respectively, so that a quick read
+	// fails faster than a write starting a long provisioning. They do not
+	// apply to the requests sent with a context which has a deadline, which
+	// is how a single call, e.g. to PutAndWait, overrides them. An operation
+	// poll is not bounded as a whole, only each of its GETs is. Zero means
+	// no timeout.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// MaxIdleConns, MaxIdleConnsPerHost and IdleConnTimeout configure the
+	// pool of the idle keep-alive connections of the internally created
+	// HTTP client, as the fields of http.Transport of the same names. The
+	// defaults keep many more connections per host than http.Transport
+	// does, as nearly all the requests go to the single management
+	// endpoint: concurrent requests then reuse the connections instead of
+	// dialing, and going through a TLS handshake, anew. They are ignored
+	// when HTTPClient is set.
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+
+	// DisableHTTP2 restricts the internally created HTTP client to HTTP/1.1.
+	// By default the client offers HTTP/2 during the TLS handshake and uses
+	// it whenever the management endpoint accepts it. Set this for endpoints
+	// which misbehave over HTTP/2.
+	DisableHTTP2 bool
+
+	// Proxy, if set, selects the proxy for each request sent by the
+	// internally created HTTP client, in place of http.ProxyFromEnvironment.
+	// See http.Transport.Proxy for its semantics. It is ignored when
+	// HTTPClient is set.
+	Proxy func(*http.Request) (*url.URL, error)
+
+	// InsecureSkipVerify disables the verification of the server certificate
+	// by the internally created HTTP client, e.g. to test against a local
+	// mock of the management API using a self-signed certificate. It is
+	// ignored when HTTPClient is set, and rejected together with the
+	// ManagementURL of a public or sovereign Azure cloud. A warning is logged
+	// through the Logger when it takes effect. Never enable it in production.
+	InsecureSkipVerify bool
+
+	// Authorizer, if set, authenticates the requests with bearer tokens
+	// instead of a management certificate. Exactly one of the two must be
+	// configured: construct the client with a nil certificate, e.g. using
+	// NewClientWithAuthorizer, when setting it.
+	Authorizer Authorizer
+
+	// Accept, if set, is sent as the Accept header of every request to
+	// select the format of the responses, e.g. "application/json" for the
+	// endpoints which support it; WithAccept overrides it for the requests
+	// sent with a given context. The error bodies are decoded according to
+	// the content type of the response, or to Accept if it has none. By
+	// default no Accept header is sent and the server picks the format.
+	Accept string
+
+	// VerifyContentMD5 makes SendAzureGetRequest verify the body of the
+	// responses carrying a Content-MD5 header against it, returning
+	// ErrChecksumMismatch if they differ. The checksum is computed while the
+	// body is read. Responses without the header are not verified.
+	VerifyContentMD5 bool
+
+	// Logger, if set, is used to log the method, path and status code of
+	// every request at debug level, annotated with the number of the retry
+	// if any, retries at info level and failures at error level. The
+	// credentials are never logged. No logging is done by default.
+	Logger Logger
+
+	// EnableTrace makes the client trace the phases of every request, i.e.
+	// the DNS lookup, the dial, the TLS handshake and the wait for the first
+	// response byte, and log their durations at debug level through the
+	// Logger; it has no effect without one. The trace hooks of the caller's
+	// context, if any, keep working. It is disabled by default, as tracing
+	// adds overhead to each request.
+	EnableTrace bool
+
+	// MaxRequestBytes, if positive, is the largest request body the client
+	// sends. Larger bodies, including the streamed ones of known length, are
+	// rejected with ErrRequestTooLarge before anything is sent. The limit
+	// applies to the body as sent, i.e. after compression. Zero means no
+	// limit.
+	MaxRequestBytes int64
+
+	// MaxResponseBytes, if positive, is the largest response body the client
+	// reads into memory, including the error bodies. Reading a larger one
+	// stops at the limit and fails with ErrResponseTooLarge. The limit
+	// applies to the body as received, i.e. after decompression. Zero means
+	// no limit.
+	MaxResponseBytes int64
+
+	// PingPath is the resource, relative to the subscription, which Ping
+	// reads to check the connectivity and credentials, e.g. "locations". If
+	// empty, the subscription itself is read.
+	PingPath string
+
+	// CancelOperation, if set, is called by CancelAllOperations to cancel
+	// an operation in progress, as the Service Management API has no
+	// cancellation common to all the operations. It returns
+	// ErrCancelNotSupported for the operations which cannot be cancelled.
+	// If nil, no operation supports cancellation.
+	CancelOperation func(ctx context.Context, client Client, operationID OperationID) error
+
+	// DryRun makes the client prepare the requests without sending them.
+	// Instead of the response, the Send* methods return a *PreparedRequest
+	// error carrying the request which would have been sent.
+	DryRun bool
+}
+
+// FromEnvironment returns a copy of the configuration with an empty
+// ManagementURL or APIVersion read from the AZURE_MANAGEMENT_URL or
+// AZURE_API_VERSION environment variables respectively. If a variable is not
+// set either, the default value is used. The precedence is thus: explicit
+// configuration, then the environment, then the defaults:
+//
+//	config := management.ClientConfig{
+//		OperationPollInterval: management.DefaultOperationPollInterval,
+//	}.FromEnvironment()
+func (c ClientConfig) FromEnvironment() ClientConfig {
+	c.ManagementURL = firstNonEmpty(c.ManagementURL, os.Getenv(EnvManagementURL), DefaultAzureManagementURL)
+	c.APIVersion = firstNonEmpty(c.APIVersion, os.Getenv(EnvAPIVersion), DefaultAPIVersion)
+	return c
 }
 
-// NewAnonymousClient creates a new azure.Client with no credentials set.
+// NewAnonymousClient creates a new azure.Client with no credentials set. As
+// the management API authenticates all the requests, those of the client fail
+// with ErrNoCredentials without being sent.
 func NewAnonymousClient() Client {
 	return client{}
 }
@@ -89,10 +507,18 @@ func NewAnonymousClient() Client {
 // configuration.
 func DefaultConfig() ClientConfig {
 	return ClientConfig{
-		ManagementURL:         DefaultAzureManagementURL,
-		OperationPollInterval: DefaultOperationPollInterval,
-		APIVersion:            DefaultAPIVersion,
-		UserAgent:             DefaultUserAgent,
+		ManagementURL:          DefaultAzureManagementURL,
+		OperationPollInterval:  DefaultOperationPollInterval,
+		OperationPollMaxErrors: DefaultOperationPollMaxErrors,
+		APIVersion:             DefaultAPIVersion,
+		UserAgent:              DefaultUserAgent,
+		RetryBackoff:           DefaultRetryBackoff,
+		DialTimeout:            DefaultDialTimeout,
+		TLSHandshakeTimeout:    DefaultTLSHandshakeTimeout,
+		ResponseHeaderTimeout:  DefaultResponseHeaderTimeout,
+		MaxIdleConns:           DefaultMaxIdleConns,
+		MaxIdleConnsPerHost:    DefaultMaxIdleConnsPerHost,
+		IdleConnTimeout:        DefaultIdleConnTimeout,
 	}
 }
 
@@ -107,6 +533,33 @@ func NewClientFromConfig(subscriptionID string, managementCert []byte, config Cl
 	return makeClient(subscriptionID, managementCert, config)
 }
 
+// NewClientFromPFX creates a new Client using a management certificate and
+// private key stored in PKCS#12 (.pfx) format. Use an empty password for
+// unprotected PFX data.
+func NewClientFromPFX(subscriptionID string, pfxData []byte, password string, config ClientConfig) (Client, error) {
+	if len(pfxData) == 0 {
+		return client{}, errors.New("azure: management certificate required")
+	}
+
+	cert, err := pfxToPEM(pfxData, password)
+	if err != nil {
+		return client{}, fmt.Errorf("azure: invalid PFX data: %w", err)
+	}
+
+	return makeClient(subscriptionID, cert, config)
+}
+
+// NewClientWithAuthorizer creates a new Client which authenticates the
+// requests using authorizer instead of a management certificate. See
+// Authorizer for the endpoints accepting token-based authentication.
+func NewClientWithAuthorizer(subscriptionID string, authorizer Authorizer, config ClientConfig) (Client, error) {
+	if authorizer == nil {
+		return client{}, errors.New("azure: authorizer required")
+	}
+	config.Authorizer = authorizer
+	return makeClient(subscriptionID, nil, config)
+}
+
 func makeClient(subscriptionID string, managementCert []byte, config ClientConfig) (Client, error) {
 	var c client
 
@@ -114,8 +567,19 @@ func makeClient(subscriptionID string, managementCert []byte, config ClientConfi
 		return c, errors.New("azure: subscription ID required")
 	}
 
-	if len(managementCert) == 0 {
+	var cert tls.Certificate
+	switch {
+	case len(managementCert) != 0 && config.Authorizer != nil:
+		return c, errors.New("azure: either a management certificate or an authorizer must be configured, not both")
+	case config.Authorizer != nil:
+		// Token-based authentication, no client certificate is presented.
+	case len(managementCert) == 0:
 		return c, errors.New("azure: management certificate required")
+	default:
+		var err error
+		if cert, err = loadCertificate(managementCert, config.CertificateChain, time.Now()); err != nil {
+			return c, err
+		}
 	}
 
 	publishSettings := publishSettings{
@@ -132,14 +596,119 @@ func makeClient(subscriptionID string, managementCert []byte, config ClientConfi
 		return c, errors.New("azure: operation polling interval must be a positive duration")
 	case config.APIVersion == "":
 		return c, errors.New("azure: client configuration must specify an API version")
+	case config.RetryBackoff < 0:
+		return c, errors.New("azure: retry backoff must not be negative")
+	case config.InsecureSkipVerify && config.HTTPClient == nil && isAzureCloudURL(config.ManagementURL):
+		return c, errors.New("azure: TLS verification may not be skipped for an Azure cloud endpoint")
 	case config.UserAgent == "":
 		config.UserAgent = DefaultUserAgent
 	}
+	if err := config.RetryBudget.validate(); err != nil {
+		return c, err
+	}
+
+	if config.Rand == nil {
+		config.Rand = newSeededRand(time.Now().UnixNano()).Float64
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = newHTTPClient(cert, config)
+	}
 
-	return client{
+	c = client{
 		publishSettings: publishSettings,
 		config:          config,
-	}, nil
+		httpClient:      httpClient,
+		state:           &clientState{},
+	}
+	if config.InsecureSkipVerify && config.HTTPClient == nil {
+		c.errorf("azure: WARNING: TLS certificate verification is disabled for %s; use InsecureSkipVerify for testing only", config.ManagementURL)
+	}
+	return c, nil
+}
+
+// azureCloudDomains are the domains of the management endpoints of the
+// public and sovereign Azure clouds.
+var azureCloudDomains = []string{
+	"core.windows.net",
+	"azure.com",
+	"chinacloudapi.cn",
+	"usgovcloudapi.net",
+	"cloudapi.de",
+}
+
+// isAzureCloudURL reports whether rawURL addresses a host in one of the
+// Azure cloud domains.
+func isAzureCloudURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	for _, domain := range azureCloudDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c client) WithManagementURL(url string) Client {
+	c.config.ManagementURL = strings.TrimSuffix(url, "/")
+	c.state = &clientState{}
+	return c
+}
+
+func (c client) Config() ClientConfig {
+	config := c.config
+	if config.CertificateChain != nil {
+		config.CertificateChain = append([]byte(nil), config.CertificateChain...)
+	}
+	return config
+}
+
+// loadCertificate parses the management certificate, appends the
+// intermediate certificates of chain to it and verifies that its leaf
+// certificate is valid at the given time and that the chain is in order.
+func loadCertificate(managementCert, chain []byte, now time.Time) (tls.Certificate, error) {
+	cert, err := tls.X509KeyPair(managementCert, managementCert)
+	if err != nil {
+		return cert, fmt.Errorf("azure: invalid management certificate: %w", err)
+	}
+	for block, rest := pem.Decode(chain); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type == "CERTIFICATE" {
+			cert.Certificate = append(cert.Certificate, block.Bytes)
+		}
+	}
+	if len(chain) != 0 && len(cert.Certificate) == 1 {
+		return cert, errors.New("azure: certificate chain holds no PEM-encoded certificate")
+	}
+
+	certs := make([]*x509.Certificate, len(cert.Certificate))
+	for i, der := range cert.Certificate {
+		if certs[i], err = x509.ParseCertificate(der); err != nil {
+			return cert, fmt.Errorf("azure: invalid management certificate: %w", err)
+		}
+	}
+	// The order of the certificates in the management certificate PEM alone
+	// has never been checked, it is only when a chain is configured.
+	for i := 1; len(chain) != 0 && i < len(certs); i++ {
+		if err := certs[i-1].CheckSignatureFrom(certs[i]); err != nil {
+			return cert, fmt.Errorf("azure: certificate chain out of order: %q is not issued by %q: %w",
+				certs[i-1].Subject.CommonName, certs[i].Subject.CommonName, err)
+		}
+	}
+	leaf := certs[0]
+
+	switch {
+	case now.After(leaf.NotAfter):
+		return cert, fmt.Errorf("azure: management certificate expired on %s", leaf.NotAfter.Format(time.RFC3339))
+	case now.Before(leaf.NotBefore):
+		return cert, fmt.Errorf("azure: management certificate is not valid before %s", leaf.NotBefore.Format(time.RFC3339))
+	}
+	cert.Leaf = leaf
+	return cert, nil
 }
 
 func userAgent() string {
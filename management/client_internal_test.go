@@ -0,0 +1,89 @@
+package management
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// testIssuer is a certificate along with its key, to issue others.
+type testIssuer struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestChain returns a root CA, an intermediate CA issued by it and the
+// PEM-encoded leaf certificate and key issued by the intermediate.
+func newTestChain(t *testing.T) (root, intermediate *x509.Certificate, leaf []byte) {
+	issue := func(name string, parent *testIssuer, ca bool) (*testIssuer, []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(time.Now().UnixNano()),
+			Subject:               pkix.Name{CommonName: name},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(time.Hour),
+			IsCA:                  ca,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		}
+		parentCert, parentKey := template, key
+		if parent != nil {
+			parentCert, parentKey = parent.cert, parent.key
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &testIssuer{cert, key}, append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})...)
+	}
+
+	rootCA, _ := issue("root", nil, true)
+	intermediateCA, _ := issue("intermediate", rootCA, true)
+	_, leaf = issue("leaf", intermediateCA, false)
+	return rootCA.cert, intermediateCA.cert, leaf
+}
+
+func encodeCertificates(certs ...*x509.Certificate) []byte {
+	var b []byte
+	for _, cert := range certs {
+		b = append(b, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	return b
+}
+
+func TestLoadCertificateChain(t *testing.T) {
+	root, intermediate, leaf := newTestChain(t)
+
+	cert, err := loadCertificate(leaf, encodeCertificates(intermediate, root), time.Now())
+	if err != nil {
+		t.Fatalf("loadCertificate()=%v", err)
+	}
+	if len(cert.Certificate) != 3 || cert.Leaf.Subject.CommonName != "leaf" {
+		t.Fatalf("got %d certificates with leaf %q, want 3 with leaf", len(cert.Certificate), cert.Leaf.Subject.CommonName)
+	}
+
+	if _, err := loadCertificate(leaf, encodeCertificates(root, intermediate), time.Now()); err == nil {
+		t.Fatal("loadCertificate() of a chain out of order succeeded")
+	}
+	if _, err := loadCertificate(leaf, []byte("garbage"), time.Now()); err == nil {
+		t.Fatal("loadCertificate() of a chain without certificates succeeded")
+	}
+}
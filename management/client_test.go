@@ -0,0 +1,186 @@
+package management_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/management"
+)
+
+func TestNewClientCertificateValidity(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	testCases := []struct {
+		notBefore, notAfter time.Time
+		wantErr             string
+	}{
+		{now.Add(-time.Hour), now.Add(time.Hour), ""},
+		{now.Add(-2 * time.Hour), now.Add(-time.Hour), "expired on " + now.Add(-time.Hour).UTC().Format(time.RFC3339)},
+		{now.Add(time.Hour), now.Add(2 * time.Hour), "not valid before " + now.Add(time.Hour).UTC().Format(time.RFC3339)},
+	}
+
+	for i, testCase := range testCases {
+		cert := newTestCert(t, testCase.notBefore, testCase.notAfter)
+		_, err := management.NewClient(testSubscriptionID, cert)
+		switch {
+		case testCase.wantErr == "" && err != nil:
+			t.Fatalf("Test %d: NewClient()=%v", i+1, err)
+		case testCase.wantErr != "" && (err == nil || !strings.Contains(err.Error(), testCase.wantErr)):
+			t.Fatalf("Test %d: got error %v, want it to contain %q", i+1, err, testCase.wantErr)
+		}
+	}
+}
+
+func TestNewClientInvalidCertificate(t *testing.T) {
+	if _, err := management.NewClient(testSubscriptionID, []byte("not a certificate")); err == nil {
+		t.Fatal("expected NewClient to fail for an invalid certificate")
+	}
+}
+
+func TestNewClientFromPFX(t *testing.T) {
+	testCases := []struct {
+		file, password string
+		wantErr        bool
+	}{
+		{"testdata/management.pfx", "", false},
+		{"testdata/management-password.pfx", "password", false},
+		{"testdata/management-password.pfx", "wrong", true},
+	}
+
+	for i, testCase := range testCases {
+		pfxData, err := ioutil.ReadFile(testCase.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = management.NewClientFromPFX(testSubscriptionID, pfxData, testCase.password, management.DefaultConfig())
+		if testCase.wantErr != (err != nil) {
+			t.Errorf("%d: NewClientFromPFX(%q) error = %v, want error: %v", i, testCase.file, err, testCase.wantErr)
+		}
+	}
+}
+
+func TestClientConfigFromEnvironment(t *testing.T) {
+	testCases := []struct {
+		config               management.ClientConfig
+		envURL, envVersion   string
+		wantURL, wantVersion string
+	}{
+		{management.ClientConfig{}, "", "", management.DefaultAzureManagementURL, management.DefaultAPIVersion},
+		{management.ClientConfig{}, "https://env", "2015-01-01", "https://env", "2015-01-01"},
+		{management.ClientConfig{ManagementURL: "https://explicit"}, "https://env", "", "https://explicit", management.DefaultAPIVersion},
+		{management.ClientConfig{APIVersion: "2016-01-01"}, "", "2015-01-01", management.DefaultAzureManagementURL, "2016-01-01"},
+	}
+
+	for i, testCase := range testCases {
+		t.Setenv(management.EnvManagementURL, testCase.envURL)
+		t.Setenv(management.EnvAPIVersion, testCase.envVersion)
+
+		config := testCase.config.FromEnvironment()
+		if config.ManagementURL != testCase.wantURL {
+			t.Fatalf("Test %d: got ManagementURL %q, want %q", i+1, config.ManagementURL, testCase.wantURL)
+		}
+		if config.APIVersion != testCase.wantVersion {
+			t.Fatalf("Test %d: got APIVersion %q, want %q", i+1, config.APIVersion, testCase.wantVersion)
+		}
+	}
+}
+
+func TestNewClientWithAuthorizer(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	client, err := management.NewClientWithAuthorizer(testSubscriptionID, management.BearerToken("token"), newTestConfig(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SendAzureGetRequest("resource"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Bearer token"; got != want {
+		t.Fatalf("got Authorization %q, want %q", got, want)
+	}
+}
+
+func TestNewClientAuthMechanisms(t *testing.T) {
+	cert := newTestCert(t, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	config := management.DefaultConfig()
+	config.Authorizer = management.BearerToken("token")
+	if _, err := management.NewClientFromConfig(testSubscriptionID, cert, config); err == nil {
+		t.Fatal("expected an error when both a certificate and an authorizer are configured")
+	}
+	if _, err := management.NewClientFromConfig(testSubscriptionID, nil, management.DefaultConfig()); err == nil {
+		t.Fatal("expected an error when no authentication is configured")
+	}
+}
+
+func TestWithManagementURL(t *testing.T) {
+	var hits [2]int
+	newServer := func(i int) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits[i]++
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	first, second := newServer(0), newServer(1)
+
+	client := newTestClientFromConfig(t, newTestConfig(first.URL))
+	other := client.WithManagementURL(second.URL + "/")
+	if _, err := other.SendAzureGetRequest("resource"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SendAzureGetRequest("resource"); err != nil {
+		t.Fatal(err)
+	}
+	if hits != [2]int{1, 1} {
+		t.Fatalf("got %v requests per server, want one each", hits)
+	}
+}
+
+func TestClientConfig(t *testing.T) {
+	config := management.DefaultConfig()
+	config.UserAgent = ""
+	client := newTestClientFromConfig(t, config)
+
+	got := client.Config()
+	if got.ManagementURL != management.DefaultAzureManagementURL {
+		t.Fatalf("got ManagementURL %q, want %q", got.ManagementURL, management.DefaultAzureManagementURL)
+	}
+	if got.APIVersion != management.DefaultAPIVersion {
+		t.Fatalf("got APIVersion %q, want %q", got.APIVersion, management.DefaultAPIVersion)
+	}
+	if got.UserAgent != management.DefaultUserAgent {
+		t.Fatalf("got UserAgent %q, want the default %q", got.UserAgent, management.DefaultUserAgent)
+	}
+
+	got.ManagementURL = "https://elsewhere.example"
+	if url := client.Config().ManagementURL; url != management.DefaultAzureManagementURL {
+		t.Fatalf("modifying the copy changed the ManagementURL of the client to %q", url)
+	}
+	if url := client.WithManagementURL("https://other.example/").Config().ManagementURL; url != "https://other.example" {
+		t.Fatalf("got ManagementURL %q of the copy, want https://other.example", url)
+	}
+}
+
+func TestAnonymousClient(t *testing.T) {
+	client := management.NewAnonymousClient()
+	if _, err := client.SendAzureGetRequest("services/hostedservices"); err != management.ErrNoCredentials {
+		t.Fatalf("SendAzureGetRequest(): got error %v, want %v", err, management.ErrNoCredentials)
+	}
+	if _, err := client.SendAzurePutRequest("services/hostedservices/name", "", nil); err != management.ErrNoCredentials {
+		t.Fatalf("SendAzurePutRequest(): got error %v, want %v", err, management.ErrNoCredentials)
+	}
+	if err := client.Ping(context.Background()); err != management.ErrNoCredentials {
+		t.Fatalf("Ping(): got error %v, want %v", err, management.ErrNoCredentials)
+	}
+	if _, err := client.WithManagementURL("https://management.example.com").GetOperationStatus("op"); err != management.ErrNoCredentials {
+		t.Fatalf("GetOperationStatus(): got error %v, want %v", err, management.ErrNoCredentials)
+	}
+}
@@ -0,0 +1,80 @@
+package management
+
+import (
+	"crypto/sha256"
+	"errors"
+	"sync"
+)
+
+// ClientSet lazily creates and caches a Client for each subscription, for
+// tools which manage many of them. It is safe for concurrent use.
+type ClientSet struct {
+	certificate func(subscriptionID string) ([]byte, error)
+	config      ClientConfig
+
+	mu      sync.Mutex
+	clients map[string]clientSetEntry
+}
+
+// clientSetEntry is a cached client along with the digest of the
+// certificate it was created with.
+type clientSetEntry struct {
+	digest [sha256.Size]byte
+	client Client
+}
+
+// NewClientSet returns a set of clients created with config, getting the
+// management certificate of each subscription from certificate.
+func NewClientSet(certificate func(subscriptionID string) ([]byte, error), config ClientConfig) *ClientSet {
+	return &ClientSet{
+		certificate: certificate,
+		config:      config,
+		clients:     make(map[string]clientSetEntry),
+	}
+}
+
+// For returns the client for the given subscription. The client is created
+// on first use and then reused for as long as the certificate returned for
+// the subscription stays the same; a changed certificate, e.g. after a
+// rotation, replaces the client.
+func (s *ClientSet) For(subscriptionID string) (Client, error) {
+	if subscriptionID == "" {
+		return nil, errors.New("azure: subscription ID required")
+	}
+	cert, err := s.certificate(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(cert)
+
+	s.mu.Lock()
+	entry, ok := s.clients[subscriptionID]
+	s.mu.Unlock()
+	if ok && entry.digest == digest {
+		return entry.client, nil
+	}
+
+	// The certificate is parsed without holding the lock, so that the
+	// clients of distinct subscriptions can be created concurrently.
+	client, err := NewClientFromConfig(subscriptionID, cert, s.config)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.clients[subscriptionID]; ok && entry.digest == digest {
+		// Created concurrently by another call.
+		return entry.client, nil
+	}
+	s.clients[subscriptionID] = clientSetEntry{digest: digest, client: client}
+	return client, nil
+}
+
+// Remove evicts the client of the given subscription, if any. The next call
+// to For creates a new one.
+func (s *ClientSet) Remove(subscriptionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.clients, subscriptionID)
+}
@@ -0,0 +1,96 @@
+package management_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/management"
+)
+
+// newTestClientSet returns a set of clients talking to a test server, using
+// the certificates in certs. As the clients cannot be compared, the tests
+// tell them apart by their server time, which is recorded from the first
+// response a client receives and is shared only by its copies.
+func newTestClientSet(t *testing.T, certs func(subscriptionID string) []byte) *management.ClientSet {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(srv.Close)
+	return management.NewClientSet(func(subscriptionID string) ([]byte, error) {
+		return certs(subscriptionID), nil
+	}, newTestConfig(srv.URL))
+}
+
+// isFresh reports whether the client has never received a response, and
+// then makes it receive one.
+func isFresh(t *testing.T, client management.Client) bool {
+	_, received := client.ServerTime()
+	if _, err := client.SendAzureGetRequest("resource"); err != nil {
+		t.Fatal(err)
+	}
+	return !received
+}
+
+func TestClientSetConcurrent(t *testing.T) {
+	cert := newTestCert(t, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	var mu sync.Mutex
+	lookups := make(map[string]int)
+	set := newTestClientSet(t, func(subscriptionID string) []byte {
+		mu.Lock()
+		lookups[subscriptionID]++
+		mu.Unlock()
+		return cert
+	})
+
+	const subscriptions, callers = 4, 8
+	var wg sync.WaitGroup
+	for i := 0; i < subscriptions; i++ {
+		for j := 0; j < callers; j++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if _, err := set.For(fmt.Sprintf("sub-%d", i)); err != nil {
+					t.Error(err)
+				}
+			}(i)
+		}
+	}
+	wg.Wait()
+
+	for i := 0; i < subscriptions; i++ {
+		subscriptionID := fmt.Sprintf("sub-%d", i)
+		client, err := set.For(subscriptionID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		isFresh(t, client)
+		if client, _ := set.For(subscriptionID); isFresh(t, client) {
+			t.Fatalf("%s: For() returned a new client for a cached subscription", subscriptionID)
+		}
+	}
+	if len(lookups) != subscriptions {
+		t.Fatalf("got certificates looked up for %d subscriptions, want %d", len(lookups), subscriptions)
+	}
+}
+
+func TestClientSetRemove(t *testing.T) {
+	cert := newTestCert(t, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	set := newTestClientSet(t, func(string) []byte { return cert })
+
+	client, err := set.For("sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	isFresh(t, client)
+	set.Remove("sub")
+	if client, _ := set.For("sub"); !isFresh(t, client) {
+		t.Fatal("For() returned the removed client")
+	}
+
+	cert = newTestCert(t, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if client, _ := set.For("sub"); !isFresh(t, client) {
+		t.Fatal("For() returned the client of the previous certificate")
+	}
+}
@@ -0,0 +1,49 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ForEachConcurrent calls fn for each of the items, with at most n calls in
+// flight at a time, e.g. to send many requests without getting throttled. A
+// failing call does not stop the others: when all of them have returned, the
+// errors are joined in the order of the items. If ctx is done first, the
+// remaining items are skipped and ctx.Err() is joined to the errors; fn is
+// expected to return early as well, as it receives ctx. n less than one
+// means one.
+func ForEachConcurrent[T any](ctx context.Context, n int, items []T, fn func(context.Context, T) error) error {
+	if n < 1 {
+		n = 1
+	}
+	errs := make([]error, len(items))
+	sem := make(chan struct{}, n)
+	var wg sync.WaitGroup
+	var ctxErr error
+
+loop:
+	for i, item := range items {
+		// Check first, as select picks randomly among the ready cases.
+		if ctxErr = ctx.Err(); ctxErr != nil {
+			break
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			ctxErr = ctx.Err()
+			break loop
+		}
+		wg.Add(1)
+		go func(i int, item T) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = fn(ctx, item)
+		}(i, item)
+	}
+	wg.Wait()
+
+	return errors.Join(append(errs, ctxErr)...)
+}
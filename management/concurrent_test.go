@@ -0,0 +1,64 @@
+package management_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/management"
+)
+
+func TestForEachConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	var inFlight, maxInFlight, calls int
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	err := management.ForEachConcurrent(context.Background(), 3, items, func(ctx context.Context, item int) error {
+		mu.Lock()
+		calls++
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		if item%4 == 0 {
+			return fmt.Errorf("item %d failed", item)
+		}
+		return nil
+	})
+	if calls != len(items) {
+		t.Fatalf("got %d calls, want %d", calls, len(items))
+	}
+	if maxInFlight > 3 {
+		t.Fatalf("got %d calls in flight, want at most 3", maxInFlight)
+	}
+	if want := "item 4 failed\nitem 8 failed"; err == nil || err.Error() != want {
+		t.Fatalf("ForEachConcurrent()=%v, want %q", err, want)
+	}
+}
+
+func TestForEachConcurrentCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	var calls int
+	err := management.ForEachConcurrent(ctx, 2, make([]int, 10), func(ctx context.Context, _ int) error {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		cancel()
+		<-ctx.Done()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ForEachConcurrent()=%v, want %v", err, context.Canceled)
+	}
+	if calls > 2 {
+		t.Fatalf("got %d calls, want the ones in flight before cancelling only", calls)
+	}
+}
@@ -1,20 +1,84 @@
 package management
 
 import (
+	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
 )
 
+// ErrChecksumMismatch is returned when the body of a response does not match
+// its Content-MD5 header. See ClientConfig.VerifyContentMD5.
+var ErrChecksumMismatch = errors.New("azure: response body does not match its Content-MD5 checksum")
+
+// ErrRequestTooLarge is returned, wrapped in an error reporting the sizes,
+// when a request body exceeds ClientConfig.MaxRequestBytes.
+var ErrRequestTooLarge = errors.New("azure: request body too large")
+
+// ErrResponseTooLarge is returned, wrapped in an error reporting the sizes,
+// when a response body exceeds ClientConfig.MaxResponseBytes.
+var ErrResponseTooLarge = errors.New("azure: response body too large")
+
+// ErrNoCredentials is returned by the requests of a client created with
+// NewAnonymousClient, which are rejected before being sent as the management
+// API authenticates all of them. Use NewClient or NewClientWithAuthorizer.
+var ErrNoCredentials = errors.New("azure: the client is anonymous, the management API requires a certificate or an authorizer")
+
 // AzureError represents an error returned by the management API. It has an error
 // code (for example, ResourceNotFound) and a descriptive message.
 type AzureError struct {
-	Code    string
-	Message string
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	// StatusCode is the HTTP status code of the response carrying the error.
+	StatusCode int `xml:"-" json:"-"`
+
+	// Method and Path identify the request which failed, when the error was
+	// returned in response to one.
+	Method string `xml:"-" json:"-"`
+	Path   string `xml:"-" json:"-"`
+
+	// RetryCount is the number of times the request was retried, after
+	// transport errors or retryable status codes alike, before the error
+	// was returned. Zero means the first attempt failed for good.
+	RetryCount int `xml:"-" json:"-"`
+
+	// CorrelationRequestID is the CorrelationRequestIDHeader of the response
+	// carrying the error, if any.
+	CorrelationRequestID string `xml:"-" json:"-"`
 }
 
 //Error implements the error interface for the AzureError type.
 func (e AzureError) Error() string {
-	return fmt.Sprintf("Error response from Azure. Code: %s, Message: %s", e.Code, e.Message)
+	msg := fmt.Sprintf("Error response from Azure. Code: %s, Message: %s", e.Code, e.Message)
+	if e.Method != "" {
+		return fmt.Sprintf("%s %s: %s", e.Method, e.Path, msg)
+	}
+	return msg
+}
+
+// SupportedVersions returns the API versions the server reported it
+// supports when rejecting the requested one, e.g. with an
+// UnsupportedApiVersion or InvalidApiVersionParameter error, or nil if the
+// message of the error does not list them. They are parsed from the message
+// on demand, which keeps AzureError comparable.
+func (e AzureError) SupportedVersions() []string {
+	return supportedVersions(e.Message)
+}
+
+// PreparedRequest is returned as an error by the clients configured for
+// a dry run. It carries the fully prepared request, with its headers and
+// body set, which the client would have sent to the management API.
+type PreparedRequest struct {
+	Request *http.Request
+}
+
+// Error implements the error interface for the PreparedRequest type.
+func (p *PreparedRequest) Error() string {
+	return fmt.Sprintf("Dry run, request not sent: %s %s", p.Request.Method, p.Request.URL)
 }
 
 // IsResourceNotFoundError returns true if the provided error is an AzureError
@@ -25,12 +89,72 @@ func IsResourceNotFoundError(err error) bool {
 }
 
 // getAzureError converts an error response body into an AzureError instance.
-func getAzureError(responseBody []byte) error {
+// The body is decoded according to its content type, either as the XML
+// <Error> element or as the JSON {"error":{...}} envelope. When the content
+// type is missing or inconclusive, both formats are tried.
+func getAzureError(responseBody []byte, contentType string) error {
+	decoders := []func([]byte) (AzureError, error){unmarshalXMLError, unmarshalJSONError}
+	if strings.Contains(strings.ToLower(contentType), "json") {
+		decoders[0], decoders[1] = decoders[1], decoders[0]
+	}
+
+	var firstErr error
+	for _, decode := range decoders {
+		azErr, err := decode(responseBody)
+		if err == nil {
+			return azErr
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return fmt.Errorf("Failed parsing contents to AzureError format: %v", firstErr)
+}
+
+func unmarshalXMLError(body []byte) (AzureError, error) {
 	var azErr AzureError
-	err := xml.Unmarshal(responseBody, &azErr)
-	if err != nil {
-		return fmt.Errorf("Failed parsing contents to AzureError format: %v", err)
+	err := xml.Unmarshal(body, &azErr)
+	return azErr, err
+}
+
+func unmarshalJSONError(body []byte) (AzureError, error) {
+	var envelope struct {
+		Error *AzureError `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return AzureError{}, err
+	}
+	if envelope.Error != nil {
+		return *envelope.Error, nil
 	}
-	return azErr
+	// Some endpoints return the error object without the envelope.
+	var azErr AzureError
+	if err := json.Unmarshal(body, &azErr); err != nil {
+		return AzureError{}, err
+	}
+	if azErr.Code == "" && azErr.Message == "" {
+		return AzureError{}, errors.New("no error object found in JSON body")
+	}
+	return azErr, nil
+}
 
+// supportedVersionsPattern matches the list of supported API versions in the
+// messages of the errors rejecting an API version, such as "The supported
+// api-versions are '2015-02-01-preview,2016-06-01'."
+var supportedVersionsPattern = regexp.MustCompile(`(?i)supported (?:api-)?versions are '([^']*)'`)
+
+// supportedVersions extracts the supported API versions listed in an error
+// message, if any.
+func supportedVersions(message string) []string {
+	m := supportedVersionsPattern.FindStringSubmatch(message)
+	if m == nil {
+		return nil
+	}
+	var versions []string
+	for _, v := range strings.Split(m[1], ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			versions = append(versions, v)
+		}
+	}
+	return versions
 }
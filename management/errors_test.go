@@ -1,7 +1,11 @@
 package management_test
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"reflect"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/management"
@@ -28,3 +32,77 @@ func TestIsResourceNotFoundError(t *testing.T) {
 		}
 	}
 }
+
+// TestAzureErrorFormats tests that both the XML and the JSON error bodies
+// are decoded into an AzureError, regardless of the reported content type.
+func TestAzureErrorFormats(t *testing.T) {
+	const (
+		xmlBody  = `<Error xmlns="http://schemas.microsoft.com/windowsazure"><Code>ResourceNotFound</Code><Message>not found</Message></Error>`
+		jsonBody = `{"error":{"code":"ResourceNotFound","message":"not found"}}`
+	)
+	testCases := []struct {
+		contentType string
+		body        string
+	}{
+		{"application/xml", xmlBody},
+		{"application/json; charset=utf-8", jsonBody},
+		{"", jsonBody},
+		{"text/plain", xmlBody},
+		{"application/xml", jsonBody},
+	}
+
+	for i, testCase := range testCases {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if testCase.contentType != "" {
+				w.Header().Set("Content-Type", testCase.contentType)
+			}
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, testCase.body)
+		}))
+
+		_, err := client.SendAzureGetRequest("resource")
+		want := management.AzureError{
+			Code:       "ResourceNotFound",
+			Message:    "not found",
+			StatusCode: http.StatusNotFound,
+			Method:     "GET",
+			Path:       "/" + testSubscriptionID + "/resource",
+		}
+		if err != want {
+			t.Fatalf("Test %d: got error %v, want %v", i+1, err, want)
+		}
+	}
+}
+
+// TestAzureErrorSupportedVersions tests that the API versions listed by an
+// error rejecting the requested one are surfaced on the AzureError.
+func TestAzureErrorSupportedVersions(t *testing.T) {
+	body, err := os.ReadFile("testdata/unsupported-api-version.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(body)
+	}))
+
+	_, err = client.SendAzureGetRequest("resource")
+	var azureErr management.AzureError
+	if !errors.As(err, &azureErr) {
+		t.Fatalf("SendAzureGetRequest()=%v, want an AzureError", err)
+	}
+	want := []string{"2016-06-01", "2015-08-01-preview", "2015-02-01-preview"}
+	if got := azureErr.SupportedVersions(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got supported versions %q, want %q", got, want)
+	}
+
+	client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `<Error><Code>BadRequest</Code><Message>bad request</Message></Error>`)
+	}))
+	_, err = client.SendAzureGetRequest("resource")
+	if !errors.As(err, &azureErr) || azureErr.SupportedVersions() != nil {
+		t.Fatalf("got %#v, want an AzureError without supported versions", err)
+	}
+}
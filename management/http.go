@@ -2,80 +2,364 @@ package management
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
 	"crypto/tls"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"time"
 )
 
 const (
 	msVersionHeader           = "x-ms-version"
 	requestIDHeader           = "x-ms-request-id"
+	asyncOperationHeader      = "Azure-AsyncOperation"
 	uaHeader                  = "User-Agent"
 	contentHeader             = "Content-Type"
+	acceptHeader              = "Accept"
 	defaultContentHeaderValue = "application/xml"
 )
 
+type acceptKey struct{}
+
+// WithAccept returns a copy of ctx making the requests sent with it, e.g. by
+// PutAndWait, carry the given Accept header, e.g. "application/json", in
+// place of ClientConfig.Accept.
+func WithAccept(ctx context.Context, accept string) context.Context {
+	return context.WithValue(ctx, acceptKey{}, accept)
+}
+
+func acceptFrom(ctx context.Context) string {
+	accept, _ := ctx.Value(acceptKey{}).(string)
+	return accept
+}
+
+// CorrelationRequestIDHeader carries the ID tying together the requests of a
+// multi-resource deployment, unlike the x-ms-request-id identifying a single
+// request. The API assigns one unless the request sets it, and returns it
+// with every response. See WithCorrelationRequestID.
+const CorrelationRequestIDHeader = "x-ms-correlation-request-id"
+
+type correlationRequestIDKey struct{}
+
+// WithCorrelationRequestID returns a copy of ctx making the requests sent with
+// it, e.g. by PutAndWait, carry id in the CorrelationRequestIDHeader, so that
+// a batch of calls can be traced end to end under a single ID. The ID is
+// reported back by AsyncOperation.CorrelationRequestID and
+// AzureError.CorrelationRequestID.
+func WithCorrelationRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationRequestIDKey{}, id)
+}
+
+func correlationRequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(correlationRequestIDKey{}).(string)
+	return id
+}
+
+// rollbackTimeout bounds the DELETE sent by CreateOrUpdateWithRollback.
+const rollbackTimeout = 5 * time.Minute
+
 func (client client) SendAzureGetRequest(url string) ([]byte, error) {
-	resp, err := client.sendAzureRequest("GET", url, "", nil)
+	return client.sendAzureGetRequest(context.Background(), url)
+}
+
+func (client client) sendAzureGetRequest(ctx context.Context, url string) ([]byte, error) {
+	body, _, err := client.getResource(ctx, url)
+	return body, err
+}
+
+// getResource works like sendAzureGetRequest, also returning the content type
+// of the response.
+func (client client) getResource(ctx context.Context, url string) (body []byte, contentType string, err error) {
+	resp, err := client.sendAzureRequest(ctx, "GET", url, "", nil)
+	if err != nil {
+		return nil, "", err
+	}
+	contentType = resp.Header.Get(contentHeader)
+	if client.config.VerifyContentMD5 {
+		body, err = getVerifiedResponseBody(resp, client.config.MaxResponseBytes)
+	} else {
+		body, err = getResponseBody(resp, client.config.MaxResponseBytes)
+	}
+	return body, contentType, err
+}
+
+// getVerifiedResponseBody reads the body of the response like
+// getResponseBody, verifying that it matches the Content-MD5 header. The
+// body is returned unverified if the header is absent, or if the transport
+// decompressed the body, as the header then covers the compressed bytes.
+func getVerifiedResponseBody(response *http.Response, max int64) ([]byte, error) {
+	header := response.Header.Get("Content-MD5")
+	if header == "" || response.Uncompressed {
+		return getResponseBody(response, max)
+	}
+	want, err := base64.StdEncoding.DecodeString(header)
+	if err != nil || len(want) != md5.Size {
+		response.Body.Close()
+		return nil, fmt.Errorf("azure: invalid Content-MD5 header %q", header)
+	}
+
+	h := md5.New()
+	response.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.TeeReader(response.Body, h), response.Body}
+	body, err := getResponseBody(response, max)
 	if err != nil {
 		return nil, err
 	}
-	return getResponseBody(resp)
+	if !bytes.Equal(h.Sum(nil), want) {
+		return nil, ErrChecksumMismatch
+	}
+	return body, nil
 }
 
 func (client client) SendAzurePostRequest(url string, data []byte) (OperationID, error) {
-	return client.doAzureOperation("POST", url, "", data)
+	return client.doAzureOperation(context.Background(), "POST", url, "", data)
 }
 
 func (client client) SendAzurePostRequestWithReturnedResponse(url string, data []byte) ([]byte, error) {
-	resp, err := client.sendAzureRequest("POST", url, "", data)
+	resp, err := client.sendAzureRequest(context.Background(), "POST", url, "", newBytesBody(data))
 	if err != nil {
 		return nil, err
 	}
 
-	return getResponseBody(resp)
+	return getResponseBody(resp, client.config.MaxResponseBytes)
+}
+
+func (client client) SendAzurePostRequestAsync(url string, data []byte) (AsyncOperation, error) {
+	response, err := client.sendAzureRequest(context.Background(), "POST", url, "", newBytesBody(data))
+	if err != nil {
+		return AsyncOperation{}, err
+	}
+	response.Body.Close()
+	return AsyncOperation{
+		RequestID:            OperationID(response.Header.Get(requestIDHeader)),
+		StatusURL:            response.Header.Get(asyncOperationHeader),
+		Location:             response.Header.Get("Location"),
+		CorrelationRequestID: response.Header.Get(CorrelationRequestIDHeader),
+	}, nil
+}
+
+func (client client) SendAzurePostRequestExpect(url string, data []byte, accepted ...int) (OperationID, error) {
+	return client.doAzureOperationExpect("POST", url, "", data, accepted)
+}
+
+func (client client) SendAzurePutRequestExpect(url, contentType string, data []byte, accepted ...int) (OperationID, error) {
+	return client.doAzureOperationExpect("PUT", url, contentType, data, accepted)
+}
+
+// doAzureOperationExpect sends the request, failing unless the response has
+// one of the accepted status codes, if any are given. Unlike
+// doAzureOperation, it does not require the response to start an operation.
+func (client client) doAzureOperationExpect(method, url, contentType string, data []byte, accepted []int) (OperationID, error) {
+	ctx := context.Background()
+	if len(accepted) != 0 {
+		ctx = context.WithValue(ctx, acceptedStatusesKey{}, accepted)
+	}
+	response, err := client.sendAzureRequest(ctx, method, url, contentType, newBytesBody(data))
+	if err != nil {
+		return "", err
+	}
+	response.Body.Close()
+	return operationIDFrom(response), nil
+}
+
+// acceptedStatusesKey is the context key of the status codes which
+// sendRequest accepts as the outcome of a request, see
+// doAzureOperationExpect.
+type acceptedStatusesKey struct{}
+
+// isAcceptedStatus reports whether code is accepted as the outcome of the
+// request. ok is false if ctx does not restrict the accepted statuses.
+func isAcceptedStatus(ctx context.Context, code int) (accepted, ok bool) {
+	codes, ok := ctx.Value(acceptedStatusesKey{}).([]int)
+	if !ok {
+		return false, false
+	}
+	for _, c := range codes {
+		if c == code {
+			return true, true
+		}
+	}
+	return false, true
 }
 
 func (client client) SendAzurePutRequest(url, contentType string, data []byte) (OperationID, error) {
-	return client.doAzureOperation("PUT", url, contentType, data)
+	return client.doAzureOperation(context.Background(), "PUT", url, contentType, data)
+}
+
+func (client client) SendAzurePutRequestGzip(url, contentType string, data []byte) (OperationID, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	body := newBytesBody(buf.Bytes())
+	body.encoding = "gzip"
+	id, err := client.doAzureStreamOperation(context.Background(), "PUT", url, contentType, body)
+	if e, ok := err.(AzureError); ok && e.StatusCode == http.StatusUnsupportedMediaType {
+		return "", fmt.Errorf("azure: the endpoint does not accept gzip-encoded request bodies: %w", err)
+	}
+	return id, err
+}
+
+func (client client) SendAzurePutRequestStream(url, contentType string, body io.Reader, length int64) (OperationID, error) {
+	return client.doAzureStreamOperation(context.Background(), "PUT", url, contentType, newStreamBody(body, length))
+}
+
+func (client client) SendAzurePutRequestStreamFunc(url, contentType string, getBody func() (io.ReadCloser, error), length int64) (OperationID, error) {
+	return client.doAzureStreamOperation(context.Background(), "PUT", url, contentType, &requestBody{getBody: getBody, length: length, replayable: true})
 }
 
 func (client client) SendAzureDeleteRequest(url string) (OperationID, error) {
-	return client.doAzureOperation("DELETE", url, "", nil)
+	return client.doAzureOperation(context.Background(), "DELETE", url, "", nil)
+}
+
+func (client client) DeleteAndWait(ctx context.Context, url string) error {
+	err := client.doAzureOperationAndWait(ctx, "DELETE", url, "", nil)
+	if azureErr, ok := err.(AzureError); ok && azureErr.StatusCode == http.StatusNotFound {
+		// The resource is already gone, which is what the caller asked for.
+		return nil
+	}
+	return err
+}
+
+func (client client) PutAndWait(ctx context.Context, url, contentType string, data []byte) error {
+	return client.doAzureOperationAndWait(ctx, "PUT", url, contentType, data)
 }
 
-func (client client) doAzureOperation(method, url, contentType string, data []byte) (OperationID, error) {
-	response, err := client.sendAzureRequest(method, url, contentType, data)
+func (client client) PostAndWait(ctx context.Context, url string, data []byte) error {
+	return client.doAzureOperationAndWait(ctx, "POST", url, "", data)
+}
+
+func (client client) doAzureOperation(ctx context.Context, method, url, contentType string, data []byte) (OperationID, error) {
+	return client.doAzureStreamOperation(ctx, method, url, contentType, newBytesBody(data))
+}
+
+func (client client) doAzureStreamOperation(ctx context.Context, method, url, contentType string, body *requestBody) (OperationID, error) {
+	response, err := client.sendAzureRequest(ctx, method, url, contentType, body)
 	if err != nil {
 		return "", err
 	}
+	defer response.Body.Close()
 	return getOperationID(response)
 }
 
+func (client client) CreateOrUpdateAndWait(ctx context.Context, url, contentType string, data []byte) error {
+	return client.doAzureOperationAndWait(ctx, "PUT", url, contentType, data)
+}
+
+func (client client) CreateOrUpdateWithRollback(ctx context.Context, url, contentType string, data []byte) (rolledBack bool, err error) {
+	err = client.CreateOrUpdateAndWait(ctx, url, contentType, data)
+	if err == nil || ctx.Err() == nil {
+		// Either the resource was created or the API refused to create it.
+		return false, err
+	}
+
+	// The request may have reached the API before ctx was done. The DELETE
+	// is not bound to ctx, nor does it inherit its values, such as the
+	// idempotency key of the PUT.
+	rollbackCtx, cancel := context.WithTimeout(context.Background(), rollbackTimeout)
+	defer cancel()
+	if deleteErr := client.DeleteAndWait(rollbackCtx, url); deleteErr != nil {
+		return false, errors.Join(err, fmt.Errorf("azure: rolling back %s: %w", url, deleteErr))
+	}
+	return true, err
+}
+
+// doAzureOperationAndWait sends the request and, if the API started a long
+// running operation, polls for its status until it completes or ctx is done.
+func (client client) doAzureOperationAndWait(ctx context.Context, method, url, contentType string, data []byte) error {
+	response, err := client.sendAzureRequest(ctx, method, url, contentType, newBytesBody(data))
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+
+	operationID := operationIDFrom(response)
+	if operationID == "" {
+		// The request completed synchronously, there is nothing to wait for.
+		return nil
+	}
+	return client.waitForOperation(ctx, operationID, nil, nil, nil)
+}
+
+// getOperationID returns the ID of the operation started by the request of
+// the response. A response without one reports ErrCompletedSynchronously,
+// unless it is a 202 Accepted, which promises an operation.
 func getOperationID(response *http.Response) (OperationID, error) {
-	requestID := response.Header.Get(requestIDHeader)
-	if requestID == "" {
+	operationID := operationIDFrom(response)
+	switch {
+	case operationID != "":
+		return operationID, nil
+	case response.StatusCode == http.StatusAccepted:
 		return "", fmt.Errorf("Could not retrieve operation id from %q header", requestIDHeader)
+	default:
+		return "", ErrCompletedSynchronously
 	}
-	return OperationID(requestID), nil
+}
+
+// operationIDFrom returns the ID of the operation started by the request of
+// the response, if any: the status URL of an ARM-style asynchronous
+// operation, or else the request ID.
+func operationIDFrom(response *http.Response) OperationID {
+	if statusURL := response.Header.Get(asyncOperationHeader); statusURL != "" {
+		return OperationID(statusURL)
+	}
+	return OperationID(response.Header.Get(requestIDHeader))
 }
 
 // sendAzureRequest constructs an HTTP client for the request, sends it to the
 // management API and returns the response or an error.
-func (client client) sendAzureRequest(method, url, contentType string, data []byte) (*http.Response, error) {
+func (client client) sendAzureRequest(ctx context.Context, method, url, contentType string, body *requestBody) (*http.Response, error) {
 	if method == "" {
 		return nil, fmt.Errorf(errParamNotSpecified, "method")
 	}
 	if url == "" {
 		return nil, fmt.Errorf(errParamNotSpecified, "url")
 	}
+	if client.isClosed() {
+		return nil, ErrClientClosed
+	}
+	if client.isAnonymous() {
+		return nil, ErrNoCredentials
+	}
+	if max := client.config.MaxRequestBytes; max > 0 && body != nil && body.length > max {
+		return nil, fmt.Errorf("%w: %d bytes, at most %d allowed", ErrRequestTooLarge, body.length, max)
+	}
 
 	httpClient, err := client.createHTTPClient()
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := client.sendRequest(httpClient, url, method, contentType, data, 5)
+	if timeout := client.methodTimeout(method); timeout > 0 && !client.config.DryRun {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			response, err := client.sendRequest(ctx, httpClient, url, method, contentType, body, 0)
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			// The timeout covers reading the body as well.
+			response.Body = cancelOnClose{response.Body, cancel}
+			return response, nil
+		}
+	}
+
+	response, err := client.sendRequest(ctx, httpClient, url, method, contentType, body, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -83,70 +367,188 @@ func (client client) sendAzureRequest(method, url, contentType string, data []by
 	return response, nil
 }
 
-// createHTTPClient creates an HTTP Client configured with the key pair for
+// methodTimeout returns the timeout configured for the requests of the given
+// method: ReadTimeout for GET and HEAD, WriteTimeout for the others.
+func (client client) methodTimeout(method string) time.Duration {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return client.config.ReadTimeout
+	}
+	return client.config.WriteTimeout
+}
+
+// cancelOnClose releases the context of a response when its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// isAnonymous reports whether the client was created by NewAnonymousClient,
+// i.e. has no means to authenticate its requests.
+func (client client) isAnonymous() bool {
+	return client.httpClient == nil && len(client.publishSettings.SubscriptionCert) == 0 && client.config.Authorizer == nil
+}
+
+// createHTTPClient returns the HTTP Client configured with the key pair for
 // the subscription for this client.
 func (client client) createHTTPClient() (*http.Client, error) {
+	if client.httpClient != nil {
+		return client.httpClient, nil
+	}
+
 	cert, err := tls.X509KeyPair(client.publishSettings.SubscriptionCert, client.publishSettings.SubscriptionKey)
 	if err != nil {
 		return nil, err
 	}
 
-	return &http.Client{
-		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			TLSClientConfig: &tls.Config{
-				Renegotiation: tls.RenegotiateOnceAsClient,
-				Certificates:  []tls.Certificate{cert},
-			},
+	return newHTTPClient(cert, client.config), nil
+}
+
+// newHTTPClient creates an HTTP Client presenting the given certificate, with
+// its transport configured according to config. No certificate is presented
+// if cert is empty.
+func newHTTPClient(cert tls.Certificate, config ClientConfig) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   config.DialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   config.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: config.ResponseHeaderTimeout,
+		MaxIdleConns:          config.MaxIdleConns,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		TLSClientConfig: &tls.Config{
+			Renegotiation:      tls.RenegotiateOnceAsClient,
+			InsecureSkipVerify: config.InsecureSkipVerify,
 		},
-	}, nil
+	}
+	if config.Proxy != nil {
+		transport.Proxy = config.Proxy
+	}
+	if len(cert.Certificate) != 0 {
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+	if config.DisableHTTP2 {
+		// A non-nil, empty map prevents the transport from upgrading to h2.
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	} else {
+		// A custom dialer and TLS config disable HTTP/2 unless it is
+		// requested explicitly.
+		transport.ForceAttemptHTTP2 = true
+	}
+
+	return &http.Client{Transport: transport}
 }
 
 // sendRequest sends a request to the Azure management API using the given
 // HTTP client and parameters. It returns the response from the call or an
 // error.
-func (client client) sendRequest(httpClient *http.Client, url, requestType, contentType string, data []byte, numberOfRetries int) (*http.Response, error) {
+func (client client) sendRequest(ctx context.Context, httpClient *http.Client, url, requestType, contentType string, body *requestBody, attempt int) (*http.Response, error) {
 
 	absURI := client.createAzureRequestURI(url)
 
 	for {
-		request, reqErr := client.createAzureRequest(absURI, requestType, contentType, data)
+		request, reqErr := client.createAzureRequest(absURI, requestType, contentType, body)
 		if reqErr != nil {
 			return nil, reqErr
 		}
+		if key := idempotencyKeyFrom(ctx); key != "" {
+			request.Header.Set(IdempotencyKeyHeader, key)
+		}
+		if accept := acceptFrom(ctx); accept != "" {
+			request.Header.Set(acceptHeader, accept)
+		}
+		if id := correlationRequestIDFrom(ctx); id != "" {
+			request.Header.Set(CorrelationRequestIDHeader, id)
+		}
+		request = request.WithContext(ctx)
+		if client.config.DryRun {
+			return nil, &PreparedRequest{Request: request}
+		}
+		var trace *requestTrace
+		if client.config.EnableTrace && client.config.Logger != nil {
+			trace = &requestTrace{}
+			request = request.WithContext(trace.withTrace(ctx))
+		}
 
 		response, err := httpClient.Do(request)
+		if trace != nil {
+			client.debugf("azure: %s %s: trace: %v", requestType, request.URL.Path, trace)
+		}
 		if err != nil {
-			if numberOfRetries == 0 {
+			if ctx.Err() != nil {
+				// Report the cancellation rather than the transport error
+				// it caused.
+				return nil, ctx.Err()
+			}
+			client.debugf("azure: %s %s: %v%s", requestType, request.URL.Path, err, retrySuffix(attempt))
+			retry, retryErr := client.shouldRetry(ctx, request, body, 0, err, attempt)
+			if retryErr != nil {
+				return nil, retryErr
+			}
+			if !retry {
 				return nil, err
 			}
 
-			return client.sendRequest(httpClient, url, requestType, contentType, data, numberOfRetries-1)
+			return client.sendRequest(ctx, httpClient, url, requestType, contentType, body, attempt+1)
 		}
+		client.recordResponse(response)
+		client.debugf("azure: %s %s: %d%s", requestType, request.URL.Path, response.StatusCode, retrySuffix(attempt))
+
 		if response.StatusCode == http.StatusTemporaryRedirect {
 			// ASM's way of moving traffic around, see https://msdn.microsoft.com/en-us/library/azure/ee460801.aspx
 			// Only handled automatically for GET/HEAD requests. This is for the rest of the http verbs.
 			u, err := response.Location()
 			if err != nil {
-				return response, fmt.Errorf("Redirect requested but location header could not be retrieved: %v", err)
+				return response, fmt.Errorf("Redirect requested but location header could not be retrieved: %w", err)
 			}
 			absURI = u.String()
 			continue // re-issue request
 		}
 
+		if accepted, ok := isAcceptedStatus(ctx, response.StatusCode); accepted {
+			return response, nil
+		} else if ok && response.StatusCode < http.StatusBadRequest {
+			response.Body.Close()
+			return nil, AzureError{
+				Code:       "UnexpectedStatus",
+				Message:    fmt.Sprintf("unexpected status %d, want one of %v", response.StatusCode, ctx.Value(acceptedStatusesKey{})),
+				StatusCode: response.StatusCode,
+				Method:     request.Method,
+				Path:       request.URL.Path,
+				RetryCount: attempt,
+
+				CorrelationRequestID: response.Header.Get(CorrelationRequestIDHeader),
+			}
+		}
+
 		if response.StatusCode >= http.StatusBadRequest {
-			body, err := getResponseBody(response)
+			responseBody, err := getResponseBody(response, client.config.MaxResponseBytes)
 			if err != nil {
 				// Failed to read the response body
 				return nil, err
 			}
-			azureErr := getAzureError(body)
+			azureErr := responseError(request, response, responseBody)
 			if azureErr != nil {
-				if numberOfRetries == 0 {
-					return nil, azureErr
+				retry, retryErr := client.shouldRetry(ctx, request, body, response.StatusCode, azureErr, attempt)
+				if retryErr != nil {
+					return nil, retryErr
+				}
+				if !retry {
+					return nil, withRetryCount(azureErr, attempt)
 				}
 
-				return client.sendRequest(httpClient, url, requestType, contentType, data, numberOfRetries-1)
+				return client.sendRequest(ctx, httpClient, url, requestType, contentType, body, attempt+1)
 			}
 		}
 
@@ -157,28 +559,73 @@ func (client client) sendRequest(httpClient *http.Client, url, requestType, cont
 // createAzureRequestURI constructs the request uri using the management API endpoint and
 // subscription ID associated with the client.
 func (client client) createAzureRequestURI(url string) string {
+	if isAbsoluteURL(url) {
+		// A status URL returned by the API, which must be used verbatim.
+		return url
+	}
 	return fmt.Sprintf("%s/%s/%s", client.config.ManagementURL, client.publishSettings.SubscriptionID, url)
 }
 
-// createAzureRequest packages up the request with the correct set of headers and returns
-// the request object or an error.
-func (client client) createAzureRequest(url string, requestType string, contentType string, data []byte) (*http.Request, error) {
-	var request *http.Request
+// responseError decodes the error carried by the body of a failed response
+// to the given request. A response without a content type is decoded in the
+// format the request asked for in its Accept header, if any. A response
+// without a body, e.g. a bare 404, is reported by its status text.
+func responseError(request *http.Request, response *http.Response, responseBody []byte) error {
+	format := response.Header.Get(contentHeader)
+	if format == "" {
+		format = request.Header.Get(acceptHeader)
+	}
 	var err error
-
-	if data != nil {
-		body := bytes.NewBuffer(data)
-		request, err = http.NewRequest(requestType, url, body)
+	if len(bytes.TrimSpace(responseBody)) == 0 {
+		err = AzureError{Code: http.StatusText(response.StatusCode), Message: response.Status}
 	} else {
-		request, err = http.NewRequest(requestType, url, nil)
+		err = getAzureError(responseBody, format)
+	}
+	if e, ok := err.(AzureError); ok {
+		e.StatusCode = response.StatusCode
+		e.Method, e.Path = request.Method, request.URL.Path
+		e.CorrelationRequestID = response.Header.Get(CorrelationRequestIDHeader)
+		return e
 	}
+	return err
+}
 
+// withRetryCount records on err, if it is an AzureError, the number of
+// retries of the request which failed with it.
+func withRetryCount(err error, attempt int) error {
+	if e, ok := err.(AzureError); ok {
+		e.RetryCount = attempt
+		return e
+	}
+	return err
+}
+
+// createAzureRequest packages up the request with the correct set of headers and returns
+// the request object or an error.
+func (client client) createAzureRequest(url string, requestType string, contentType string, body *requestBody) (*http.Request, error) {
+	request, err := http.NewRequest(requestType, url, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	if body != nil {
+		if request.Body, err = body.getBody(); err != nil {
+			return nil, err
+		}
+		request.ContentLength = body.length
+		if body.replayable {
+			request.GetBody = body.getBody
+		}
+		if body.encoding != "" {
+			request.Header.Set("Content-Encoding", body.encoding)
+		}
+	}
+
 	request.Header.Set(msVersionHeader, client.config.APIVersion)
 	request.Header.Set(uaHeader, client.config.UserAgent)
+	if client.config.Accept != "" {
+		request.Header.Set(acceptHeader, client.config.Accept)
+	}
 
 	if contentType != "" {
 		request.Header.Set(contentHeader, contentType)
@@ -186,5 +633,75 @@ func (client client) createAzureRequest(url string, requestType string, contentT
 		request.Header.Set(contentHeader, defaultContentHeaderValue)
 	}
 
+	if client.config.Authorizer != nil {
+		if err := client.config.Authorizer.Authorize(request); err != nil {
+			return nil, fmt.Errorf("azure: failed to authorize request: %w", err)
+		}
+	}
+
 	return request, nil
 }
+
+// requestBody is the payload of a request to the management API.
+type requestBody struct {
+	// getBody returns a new reader of the payload for each attempt.
+	getBody func() (io.ReadCloser, error)
+	length  int64
+
+	// encoding is the Content-Encoding of the payload, if any.
+	encoding string
+
+	// replayable is false when getBody can be called only once, so the
+	// request cannot be retried or redirected.
+	replayable bool
+	consumed   bool
+}
+
+// newBytesBody returns a replayable body reading data, or nil if data is nil.
+func newBytesBody(data []byte) *requestBody {
+	if data == nil {
+		return nil
+	}
+	return &requestBody{
+		getBody: func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(data)), nil
+		},
+		length:     int64(len(data)),
+		replayable: true,
+	}
+}
+
+// newStreamBody returns a body reading from r. The body is replayable only
+// if r is an io.Seeker, in which case it is rewound to its initial offset
+// before each attempt.
+func newStreamBody(r io.Reader, length int64) *requestBody {
+	if seeker, ok := r.(io.Seeker); ok {
+		if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			return &requestBody{
+				getBody: func() (io.ReadCloser, error) {
+					if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+						return nil, err
+					}
+					return ioutil.NopCloser(r), nil
+				},
+				length:     length,
+				replayable: true,
+			}
+		}
+	}
+
+	body := &requestBody{length: length}
+	body.getBody = func() (io.ReadCloser, error) {
+		if body.consumed {
+			return nil, errors.New("azure: request body cannot be re-sent")
+		}
+		body.consumed = true
+		return ioutil.NopCloser(r), nil
+	}
+	return body
+}
+
+// canRetry reports whether the request with this body can be sent again.
+func (body *requestBody) canRetry() bool {
+	return body == nil || body.replayable
+}
@@ -0,0 +1,115 @@
+package management
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTP2Negotiation(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	testCases := []struct {
+		disableHTTP2 bool
+		wantProto    int
+	}{
+		{false, 2},
+		{true, 1},
+	}
+	for i, testCase := range testCases {
+		config := DefaultConfig()
+		config.DisableHTTP2 = testCase.disableHTTP2
+		config.DialTimeout = time.Second
+
+		httpClient := newHTTPClient(srv.TLS.Certificates[0], config)
+		httpClient.Transport.(*http.Transport).TLSClientConfig.RootCAs = roots
+
+		response, err := httpClient.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		response.Body.Close()
+		if response.ProtoMajor != testCase.wantProto {
+			t.Errorf("Test %d: negotiated %s, want HTTP/%d", i+1, response.Proto, testCase.wantProto)
+		}
+	}
+}
+
+func TestIdleConnSettings(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxIdleConns = 10
+	config.MaxIdleConnsPerHost = 5
+	config.IdleConnTimeout = time.Second
+
+	transport := newHTTPClient(tls.Certificate{}, config).Transport.(*http.Transport)
+	if transport.MaxIdleConns != 10 || transport.MaxIdleConnsPerHost != 5 || transport.IdleConnTimeout != time.Second {
+		t.Fatalf("got MaxIdleConns=%d, MaxIdleConnsPerHost=%d and IdleConnTimeout=%v, want the configured ones",
+			transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.IdleConnTimeout)
+	}
+}
+
+// BenchmarkIdleConns sends batches of concurrent requests, reporting the
+// number of connections dialed per batch with the idle connection limits of
+// http.Transport and with the defaults of DefaultConfig.
+func BenchmarkIdleConns(b *testing.B) {
+	const concurrency = 16
+	benchmarks := []struct {
+		name                string
+		maxIdleConnsPerHost int
+	}{
+		{"Transport", http.DefaultMaxIdleConnsPerHost},
+		{"DefaultConfig", DefaultMaxIdleConnsPerHost},
+	}
+	for _, benchmark := range benchmarks {
+		b.Run(benchmark.name, func(b *testing.B) {
+			var dials int64
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					atomic.AddInt64(&dials, 1)
+				}
+			}
+			srv.Start()
+			defer srv.Close()
+
+			config := DefaultConfig()
+			config.MaxIdleConnsPerHost = benchmark.maxIdleConnsPerHost
+			httpClient := newHTTPClient(tls.Certificate{}, config)
+			defer httpClient.CloseIdleConnections()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var wg sync.WaitGroup
+				for j := 0; j < concurrency; j++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						response, err := httpClient.Get(srv.URL)
+						if err != nil {
+							b.Error(err)
+							return
+						}
+						io.Copy(ioutil.Discard, response.Body)
+						response.Body.Close()
+					}()
+				}
+				wg.Wait()
+			}
+			b.ReportMetric(float64(atomic.LoadInt64(&dials))/float64(b.N), "dials/op")
+		})
+	}
+}
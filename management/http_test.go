@@ -0,0 +1,756 @@
+package management_test
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/management"
+)
+
+// newSilentListener returns a listener which accepts connections, but never
+// writes anything to them. It is closed when the test ends.
+func newSilentListener(t *testing.T) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, c := range conns {
+				c.Close()
+			}
+		}()
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, c)
+		}
+	}()
+	return l
+}
+
+func TestTransportTimeouts(t *testing.T) {
+	l := newSilentListener(t)
+
+	testCases := []struct {
+		name                  string
+		scheme                string
+		tlsHandshakeTimeout   time.Duration
+		responseHeaderTimeout time.Duration
+	}{
+		{"ResponseHeaderTimeout", "http", 0, 20 * time.Millisecond},
+		{"TLSHandshakeTimeout", "https", 20 * time.Millisecond, 0},
+	}
+
+	for _, testCase := range testCases {
+		config := newTestConfig(testCase.scheme + "://" + l.Addr().String())
+		config.TLSHandshakeTimeout = testCase.tlsHandshakeTimeout
+		config.ResponseHeaderTimeout = testCase.responseHeaderTimeout
+		client := newTestClientFromConfig(t, config)
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := client.SendAzureGetRequest("resource")
+			done <- err
+		}()
+
+		select {
+		case err := <-done:
+			if err == nil {
+				t.Fatalf("%s: expected the request to fail", testCase.name)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: request did not time out", testCase.name)
+		}
+	}
+}
+
+// flakyHandler fails the first request with a server error and records
+// the bodies of all the requests it receives.
+func flakyHandler(bodies *[]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		*bodies = append(*bodies, string(b))
+		if len(*bodies) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `<Error><Code>InternalError</Code><Message>try again</Message></Error>`)
+			return
+		}
+		w.Header().Set("x-ms-request-id", "op")
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+func TestSendAzurePutRequestStream(t *testing.T) {
+	const data = "<Deployment/>"
+
+	var bodies []string
+	client := newTestClient(t, flakyHandler(&bodies))
+
+	id, err := client.SendAzurePutRequestStream("resource", "", strings.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("SendAzurePutRequestStream()=%v", err)
+	}
+	if id != "op" {
+		t.Fatalf("got operation ID %q, want %q", id, "op")
+	}
+	if want := []string{data, data}; !reflect.DeepEqual(bodies, want) {
+		t.Fatalf("got bodies %q, want %q", bodies, want)
+	}
+}
+
+func TestSendAzurePutRequestStreamNotReplayable(t *testing.T) {
+	const data = "<Deployment/>"
+
+	var bodies []string
+	client := newTestClient(t, flakyHandler(&bodies))
+
+	r := ioutil.NopCloser(strings.NewReader(data)) // hides io.Seeker
+	if _, err := client.SendAzurePutRequestStream("resource", "", r, int64(len(data))); err == nil {
+		t.Fatal("expected the request to fail without retrying")
+	}
+	if len(bodies) != 1 {
+		t.Fatalf("got %d requests, want 1", len(bodies))
+	}
+}
+
+func TestSendAzurePutRequestStreamFunc(t *testing.T) {
+	const data = "<Deployment/>"
+
+	var bodies []string
+	client := newTestClient(t, flakyHandler(&bodies))
+
+	getBody := func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader(data)), nil
+	}
+	if _, err := client.SendAzurePutRequestStreamFunc("resource", "", getBody, int64(len(data))); err != nil {
+		t.Fatalf("SendAzurePutRequestStreamFunc()=%v", err)
+	}
+	if want := []string{data, data}; !reflect.DeepEqual(bodies, want) {
+		t.Fatalf("got bodies %q, want %q", bodies, want)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	const data = "<Deployment/>"
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer srv.Close()
+
+	config := newTestConfig(srv.URL)
+	config.DryRun = true
+	client := newTestClientFromConfig(t, config)
+
+	_, err := client.SendAzurePutRequest("resource", "application/json", []byte(data))
+	prepared, ok := err.(*management.PreparedRequest)
+	if !ok {
+		t.Fatalf("got error %v, want *management.PreparedRequest", err)
+	}
+	if requests != 0 {
+		t.Fatalf("got %d requests sent, want 0", requests)
+	}
+
+	req := prepared.Request
+	if want := srv.URL + "/" + testSubscriptionID + "/resource"; req.Method != "PUT" || req.URL.String() != want {
+		t.Fatalf("got %s %s, want PUT %s", req.Method, req.URL, want)
+	}
+	if got := req.Header.Get("Content-Type"); got != "application/json" {
+		t.Fatalf("got Content-Type %q, want %q", got, "application/json")
+	}
+	if b, _ := ioutil.ReadAll(req.Body); string(b) != data {
+		t.Fatalf("got body %q, want %q", b, data)
+	}
+}
+
+func TestSendAzurePostRequestNotRetried(t *testing.T) {
+	var bodies []string
+	client := newTestClient(t, flakyHandler(&bodies))
+
+	if _, err := client.SendAzurePostRequest("resource", []byte("<Action/>")); err == nil {
+		t.Fatal("expected the request to fail without retrying")
+	}
+	if len(bodies) != 1 {
+		t.Fatalf("got %d requests, want 1", len(bodies))
+	}
+}
+
+func TestPostAndWaitIdempotencyKey(t *testing.T) {
+	var bodies []string
+	var keys []string
+	flaky := flakyHandler(&bodies)
+	mux := http.NewServeMux()
+	mux.Handle("/"+testSubscriptionID+"/operations/", operationStatusHandler(operationStatus("Succeeded", "")))
+	mux.HandleFunc("/"+testSubscriptionID+"/resource", func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(management.IdempotencyKeyHeader))
+		flaky.ServeHTTP(w, r)
+	})
+	client := newTestClient(t, mux)
+
+	ctx := management.WithIdempotencyKey(context.Background(), "key")
+	if err := client.PostAndWait(ctx, "resource", []byte("<Action/>")); err != nil {
+		t.Fatalf("PostAndWait()=%v", err)
+	}
+	if want := []string{"key", "key"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("got idempotency keys %q, want %q", keys, want)
+	}
+}
+
+// failingTransport fails the first len(errs) round trips with the given
+// errors and answers the following ones with 200 OK.
+type failingTransport struct {
+	errs     []error
+	requests int
+}
+
+func (t *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	if t.requests <= len(t.errs) {
+		return nil, t.errs[t.requests-1]
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"X-Ms-Request-Id": {"op"}},
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func TestTransportErrorRetries(t *testing.T) {
+	testCases := []struct {
+		err          error
+		wantRequests int
+		wantErr      bool
+	}{
+		{&net.DNSError{Err: "no such host", Name: "management.core.windows.net", IsTemporary: true}, 2, false},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, 2, false},
+		{&net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}, 1, true},
+		{x509.UnknownAuthorityError{}, 1, true},
+	}
+
+	for i, testCase := range testCases {
+		transport := &failingTransport{errs: []error{testCase.err}}
+		config := newTestConfig("https://management.core.windows.net")
+		config.HTTPClient = &http.Client{Transport: transport}
+		client := newTestClientFromConfig(t, config)
+
+		_, err := client.SendAzurePostRequest("resource", []byte("<Action/>"))
+		if (err != nil) != testCase.wantErr {
+			t.Errorf("Test %d: SendAzurePostRequest()=%v, want error: %t", i+1, err, testCase.wantErr)
+		}
+		if transport.requests != testCase.wantRequests {
+			t.Errorf("Test %d: got %d requests, want %d", i+1, transport.requests, testCase.wantRequests)
+		}
+	}
+}
+
+func TestCertificateErrorNotRetried(t *testing.T) {
+	transport := &failingTransport{errs: []error{x509.HostnameError{Certificate: &x509.Certificate{}, Host: "example.com"}}}
+	config := newTestConfig("https://management.core.windows.net")
+	config.HTTPClient = &http.Client{Transport: transport}
+	client := newTestClientFromConfig(t, config)
+
+	if _, err := client.SendAzureGetRequest("resource"); err == nil {
+		t.Fatal("expected the request to fail")
+	}
+	if transport.requests != 1 {
+		t.Fatalf("got %d requests, want 1", transport.requests)
+	}
+}
+
+func TestProxy(t *testing.T) {
+	var got string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.String()
+	}))
+	defer proxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := newTestConfig("http://management.example")
+	config.Proxy = http.ProxyURL(proxyURL)
+	client := newTestClientFromConfig(t, config)
+	if _, err := client.SendAzureGetRequest("resource"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "http://management.example/" + testSubscriptionID + "/resource"; got != want {
+		t.Fatalf("proxy got request for %q, want %q", got, want)
+	}
+}
+
+func TestSendAzurePutRequestGzip(t *testing.T) {
+	const data = "<Deployment><Configuration>large</Configuration></Deployment>"
+
+	var got string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			fmt.Fprint(w, `<Error><Code>UnsupportedMediaType</Code><Message>gzip only</Message></Error>`)
+			return
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		b, _ := ioutil.ReadAll(zr)
+		got = string(b)
+		w.Header().Set("x-ms-request-id", "op")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	if _, err := client.SendAzurePutRequestGzip("resource", "", []byte(data)); err != nil {
+		t.Fatalf("SendAzurePutRequestGzip()=%v", err)
+	}
+	if got != data {
+		t.Fatalf("server got %q, want %q", got, data)
+	}
+}
+
+func TestSendAzurePutRequestGzipRejected(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		fmt.Fprint(w, `<Error><Code>UnsupportedMediaType</Code><Message>no gzip</Message></Error>`)
+	}))
+
+	_, err := client.SendAzurePutRequestGzip("resource", "", []byte("<Deployment/>"))
+	var azureErr management.AzureError
+	if !errors.As(err, &azureErr) || azureErr.StatusCode != http.StatusUnsupportedMediaType {
+		t.Fatalf("got error %v, want it to wrap a 415 AzureError", err)
+	}
+}
+
+func TestVerifyContentMD5(t *testing.T) {
+	const body = "<Operation><Status>Succeeded</Status></Operation>"
+	sum := md5.Sum([]byte(body))
+	valid := base64.StdEncoding.EncodeToString(sum[:])
+	corrupted := base64.StdEncoding.EncodeToString(make([]byte, md5.Size))
+
+	testCases := []struct {
+		header string
+		verify bool
+		err    error
+	}{
+		{valid, true, nil},
+		{"", true, nil},
+		{corrupted, true, management.ErrChecksumMismatch},
+		{corrupted, false, nil},
+	}
+	for i, testCase := range testCases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if testCase.header != "" {
+				w.Header().Set("Content-MD5", testCase.header)
+			}
+			fmt.Fprint(w, body)
+		}))
+		config := newTestConfig(srv.URL)
+		config.VerifyContentMD5 = testCase.verify
+		client := newTestClientFromConfig(t, config)
+
+		got, err := client.SendAzureGetRequest("resource")
+		srv.Close()
+		if err != testCase.err {
+			t.Fatalf("Test %d: got error %v, want %v", i+1, err, testCase.err)
+		}
+		if err == nil && string(got) != body {
+			t.Fatalf("Test %d: got body %q, want %q", i+1, got, body)
+		}
+	}
+}
+
+// recordingLogger records the messages logged at each level.
+type recordingLogger struct {
+	mu                   sync.Mutex
+	debug, info, errored []string
+}
+
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.debug = append(l.debug, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) Infof(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.info = append(l.info, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) Errorf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errored = append(l.errored, fmt.Sprintf(format, args...))
+}
+
+func TestLogger(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `<Error><Code>ServiceUnavailable</Code><Message>busy</Message></Error>`)
+		}
+	}))
+	defer srv.Close()
+
+	logger := &recordingLogger{}
+	config := newTestConfig(srv.URL)
+	config.Logger = logger
+	client := newTestClientFromConfig(t, config)
+	if _, err := client.SendAzureGetRequest("resource"); err != nil {
+		t.Fatalf("SendAzureGetRequest()=%v", err)
+	}
+
+	path := "/" + testSubscriptionID + "/resource"
+	wantDebug := []string{"azure: GET " + path + ": 503", "azure: GET " + path + ": 200 (retry 1)"}
+	if !reflect.DeepEqual(logger.debug, wantDebug) {
+		t.Fatalf("got debug messages %q, want %q", logger.debug, wantDebug)
+	}
+	if len(logger.info) != 1 || !strings.Contains(logger.info[0], "retrying") {
+		t.Fatalf("got info messages %q, want a single retry", logger.info)
+	}
+	if len(logger.errored) != 0 {
+		t.Fatalf("got error messages %q, want none", logger.errored)
+	}
+}
+
+func TestRetryCount(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			// Fail the first attempt with a transport error.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `<Error><Code>ServiceUnavailable</Code><Message>busy</Message></Error>`)
+	}))
+	defer srv.Close()
+
+	config := newTestConfig(srv.URL)
+	config.RetryPolicy = management.DefaultRetryPolicy{MaxRetries: 3}
+	client := newTestClientFromConfig(t, config)
+	_, err := client.SendAzureGetRequest("resource")
+	var azureErr management.AzureError
+	if !errors.As(err, &azureErr) {
+		t.Fatalf("SendAzureGetRequest()=%v, want an AzureError", err)
+	}
+	if azureErr.RetryCount != 3 {
+		t.Fatalf("got RetryCount %d, want 3", azureErr.RetryCount)
+	}
+	if calls != 4 {
+		t.Fatalf("got %d attempts, want 4", calls)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `<Error><Code>ServiceUnavailable</Code><Message>busy</Message></Error>`)
+	}))
+	defer srv.Close()
+
+	config := newTestConfig(srv.URL)
+	config.RetryPolicy = management.DefaultRetryPolicy{MaxRetries: 3}
+	config.RetryBudget = management.RetryBudget{Window: time.Hour, MaxRetries: 2}
+	client := newTestClientFromConfig(t, config)
+
+	// The first request spends the budget, the next ones fail fast.
+	for i, want := range []int{3, 1, 1} {
+		calls = 0
+		_, err := client.SendAzureGetRequest("resource")
+		var azureErr management.AzureError
+		if !errors.As(err, &azureErr) || azureErr.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("Request %d: got error %v, want the 503 AzureError", i+1, err)
+		}
+		if calls != want {
+			t.Fatalf("Request %d: got %d attempts, want %d", i+1, calls, want)
+		}
+	}
+
+	config.RetryBudget = management.RetryBudget{MaxRetries: 2}
+	cert := newTestCert(t, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if _, err := management.NewClientFromConfig(testSubscriptionID, cert, config); err == nil || !strings.Contains(err.Error(), "window") {
+		t.Fatalf("got error %v, want one for a retry budget without a window", err)
+	}
+}
+
+func TestCompletedSynchronously(t *testing.T) {
+	status := http.StatusOK
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+
+	id, err := client.SendAzurePostRequest("resource", nil)
+	if !errors.Is(err, management.ErrCompletedSynchronously) || id != "" {
+		t.Fatalf("SendAzurePostRequest()=(%q, %v), want (\"\", %v)", id, err, management.ErrCompletedSynchronously)
+	}
+	if _, err := client.SendAzureDeleteRequest("resource"); !errors.Is(err, management.ErrCompletedSynchronously) {
+		t.Fatalf("SendAzureDeleteRequest()=%v, want %v", err, management.ErrCompletedSynchronously)
+	}
+
+	status = http.StatusAccepted
+	if _, err := client.SendAzurePutRequest("resource", "", nil); err == nil || errors.Is(err, management.ErrCompletedSynchronously) {
+		t.Fatalf("SendAzurePutRequest() of a 202 without a request ID = %v, want a missing header error", err)
+	}
+}
+
+func TestAccept(t *testing.T) {
+	var accepts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accepts = append(accepts, r.Header.Get("Accept"))
+		// No content type, the error is decoded as negotiated.
+		w.Header()["Content-Type"] = nil
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"code":"ResourceNotFound","message":"gone"}}`)
+	}))
+	defer srv.Close()
+
+	config := newTestConfig(srv.URL)
+	newTestClientFromConfig(t, config).SendAzureGetRequest("resource")
+
+	config.Accept = "application/json"
+	client := newTestClientFromConfig(t, config)
+	_, err := client.SendAzureGetRequest("resource")
+	if !management.IsResourceNotFoundError(err) {
+		t.Fatalf("SendAzureGetRequest()=%v, want a ResourceNotFound error", err)
+	}
+	ctx := management.WithAccept(context.Background(), "application/xml")
+	if err := client.DeleteAndWait(ctx, "resource"); err != nil {
+		t.Fatalf("DeleteAndWait()=%v", err)
+	}
+
+	if want := []string{"", "application/json", "application/xml"}; !reflect.DeepEqual(accepts, want) {
+		t.Fatalf("got Accept headers %q, want %q", accepts, want)
+	}
+}
+
+func TestCorrelationRequestID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(management.CorrelationRequestIDHeader)
+		if id == "" {
+			id = "assigned"
+		}
+		w.Header().Set(management.CorrelationRequestIDHeader, id)
+		if r.Method == http.MethodPost {
+			w.Header().Set("x-ms-request-id", "op")
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `<Error><Code>ConflictError</Code><Message>taken</Message></Error>`)
+	}))
+	defer srv.Close()
+	client := newTestClientFromConfig(t, newTestConfig(srv.URL))
+
+	op, err := client.SendAzurePostRequestAsync("resource", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op.CorrelationRequestID != "assigned" {
+		t.Fatalf("got correlation request ID %q, want the assigned one", op.CorrelationRequestID)
+	}
+
+	ctx := management.WithCorrelationRequestID(context.Background(), "batch")
+	err = client.PutAndWait(ctx, "resource", "", nil)
+	var azureErr management.AzureError
+	if !errors.As(err, &azureErr) || azureErr.CorrelationRequestID != "batch" {
+		t.Fatalf("PutAndWait()=%#v, want an error with the correlation request ID of the batch", err)
+	}
+}
+
+func TestInsecureSkipVerify(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	config := newTestConfig(srv.URL)
+	if _, err := newTestClientFromConfig(t, config).SendAzureGetRequest("resource"); err == nil {
+		t.Fatal("SendAzureGetRequest() to a self-signed server succeeded without InsecureSkipVerify")
+	}
+
+	logger := &recordingLogger{}
+	config.InsecureSkipVerify = true
+	config.Logger = logger
+	if _, err := newTestClientFromConfig(t, config).SendAzureGetRequest("resource"); err != nil {
+		t.Fatalf("SendAzureGetRequest()=%v", err)
+	}
+	if len(logger.errored) != 1 || !strings.Contains(logger.errored[0], "verification is disabled") {
+		t.Fatalf("got error messages %q, want a single warning", logger.errored)
+	}
+
+	cert := newTestCert(t, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	for _, url := range []string{
+		management.DefaultAzureManagementURL,
+		"https://management.chinacloudapi.cn",
+		"https://management.usgovcloudapi.net",
+		"https://management.core.cloudapi.de",
+		"https://management.azure.com",
+	} {
+		config := newTestConfig(url)
+		config.InsecureSkipVerify = true
+		if _, err := management.NewClientFromConfig(testSubscriptionID, cert, config); err == nil {
+			t.Errorf("NewClientFromConfig() with InsecureSkipVerify for %s succeeded", url)
+		}
+	}
+}
+
+func TestEnableTrace(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	var callerGotConn bool
+	ctx := httptrace.WithClientTrace(context.Background(), &httptrace.ClientTrace{
+		GotConn: func(httptrace.GotConnInfo) { callerGotConn = true },
+	})
+	logger := &recordingLogger{}
+	config := newTestConfig(srv.URL)
+	config.Logger = logger
+	config.EnableTrace = true
+	client := newTestClientFromConfig(t, config)
+	if err := client.DeleteAndWait(ctx, "resource"); err != nil {
+		t.Fatalf("DeleteAndWait()=%v", err)
+	}
+
+	if !callerGotConn {
+		t.Fatal("the trace hooks of the caller's context were not called")
+	}
+	if len(logger.debug) == 0 || !strings.Contains(logger.debug[0], "trace: ") || !strings.Contains(logger.debug[0], "first byte=") {
+		t.Fatalf("got debug messages %q, want a trace with the time to first byte", logger.debug)
+	}
+}
+
+func TestMaxRequestBytes(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("x-ms-request-id", "op")
+	}))
+	defer srv.Close()
+
+	config := newTestConfig(srv.URL)
+	config.MaxRequestBytes = 8
+	client := newTestClientFromConfig(t, config)
+
+	if _, err := client.SendAzurePutRequest("resource", "", []byte("<Small/>")); err != nil {
+		t.Fatalf("SendAzurePutRequest() of %d bytes: %v", len("<Small/>"), err)
+	}
+	_, err := client.SendAzurePostRequest("resource", []byte("<TooLarge/>"))
+	if !errors.Is(err, management.ErrRequestTooLarge) || !strings.Contains(err.Error(), "11 bytes, at most 8") {
+		t.Fatalf("got error %v, want %v reporting the sizes", err, management.ErrRequestTooLarge)
+	}
+	if requests != 1 {
+		t.Fatalf("got %d requests, want 1", requests)
+	}
+}
+
+func TestMethodTimeouts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			time.Sleep(50 * time.Millisecond)
+		}
+		fmt.Fprint(w, "body")
+	}))
+	defer srv.Close()
+
+	config := newTestConfig(srv.URL)
+	config.ReadTimeout = time.Second
+	config.WriteTimeout = 10 * time.Millisecond
+	config.RetryPolicy = management.DefaultRetryPolicy{}
+	client := newTestClientFromConfig(t, config)
+	if body, err := client.SendAzureGetRequest("resource"); err != nil || string(body) != "body" {
+		t.Fatalf("SendAzureGetRequest()=(%q, %v), want the body", body, err)
+	}
+	if _, err := client.SendAzurePutRequest("resource", "", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SendAzurePutRequest()=%v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// A deadline of the caller overrides the timeout.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.PutAndWait(ctx, "resource", "", nil); err != nil && errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("PutAndWait()=%v, want the deadline of the context to apply", err)
+	}
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	body := strings.Repeat("x", 100)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	config := newTestConfig(srv.URL)
+	config.MaxResponseBytes = 100
+	got, err := newTestClientFromConfig(t, config).SendAzureGetRequest("resource")
+	if err != nil || string(got) != body {
+		t.Fatalf("SendAzureGetRequest() at the limit = (%d bytes, %v), want the body", len(got), err)
+	}
+
+	config.MaxResponseBytes = 10
+	_, err = newTestClientFromConfig(t, config).SendAzureGetRequest("resource")
+	if !errors.Is(err, management.ErrResponseTooLarge) {
+		t.Fatalf("SendAzureGetRequest()=%v, want %v", err, management.ErrResponseTooLarge)
+	}
+	if !strings.Contains(err.Error(), "read 11 bytes") {
+		t.Fatalf("got error %q, want it to report the bytes read", err)
+	}
+}
+
+func TestSendAzurePostRequestExpect(t *testing.T) {
+	testCases := []struct {
+		status   int
+		accepted []int
+		wantErr  bool
+	}{
+		{http.StatusAccepted, []int{http.StatusOK, http.StatusAccepted}, false},
+		{http.StatusOK, nil, false},
+		{http.StatusCreated, []int{http.StatusAccepted}, true},
+		{http.StatusConflict, []int{http.StatusConflict}, false},
+		{http.StatusConflict, nil, true},
+	}
+	for i, testCase := range testCases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(testCase.status)
+		}))
+		client := newTestClientFromConfig(t, newTestConfig(srv.URL))
+		_, err := client.SendAzurePostRequestExpect("resource", []byte("<Request/>"), testCase.accepted...)
+		srv.Close()
+		if (err != nil) != testCase.wantErr {
+			t.Fatalf("Test %d: got error %v for status %d accepting %v, want error %t", i+1, err, testCase.status, testCase.accepted, testCase.wantErr)
+		}
+	}
+}
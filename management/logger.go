@@ -0,0 +1,41 @@
+package management
+
+import "fmt"
+
+// Logger receives the request lifecycle events of a client, see
+// ClientConfig.Logger. Implementations must be safe for concurrent use.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// The helpers below check for a nil logger before formatting anything, so
+// that logging costs a single comparison when it is disabled.
+
+func (client client) debugf(format string, args ...interface{}) {
+	if client.config.Logger != nil {
+		client.config.Logger.Debugf(format, args...)
+	}
+}
+
+func (client client) infof(format string, args ...interface{}) {
+	if client.config.Logger != nil {
+		client.config.Logger.Infof(format, args...)
+	}
+}
+
+func (client client) errorf(format string, args ...interface{}) {
+	if client.config.Logger != nil {
+		client.config.Logger.Errorf(format, args...)
+	}
+}
+
+// retrySuffix annotates the messages logged for a retried request with the
+// number of the retry, so that the attempts a request took can be counted.
+func retrySuffix(attempt int) string {
+	if attempt == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (retry %d)", attempt)
+}
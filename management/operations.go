@@ -1,9 +1,15 @@
 package management
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"strings"
 	"time"
 )
 
@@ -11,6 +17,17 @@ var (
 	// ErrOperationCancelled from WaitForOperation when the polling loop is
 	// cancelled through signaling the channel.
 	ErrOperationCancelled = errors.New("Polling for operation status cancelled")
+
+	// ErrOperationNotFound is returned when the API does not know the
+	// operation, either because it has never existed or because it has
+	// already expired.
+	ErrOperationNotFound = errors.New("Operation not found")
+
+	// ErrCompletedSynchronously is returned, along with an empty
+	// OperationID, by the send methods returning an OperationID when the
+	// API completed the request synchronously, without starting an
+	// operation to wait for: the request succeeded and must not be polled.
+	ErrCompletedSynchronously = errors.New("azure: request completed synchronously, there is no operation to wait for")
 )
 
 // GetOperationStatusResponse represents an in-flight operation. Use
@@ -23,6 +40,27 @@ type GetOperationStatusResponse struct {
 	Status         OperationStatus
 	HTTPStatusCode string
 	Error          *AzureError
+
+	// PercentComplete is set only for the operations that report their
+	// progress in the status payload (either as PercentComplete or Progress).
+	PercentComplete *int `xml:"-"`
+
+	// StartTime and EndTime are the times the operation started and, once
+	// it has completed, ended at, for the operations that report them in
+	// the status payload. They are zero when missing, empty or not in the
+	// RFC 3339 format.
+	StartTime time.Time `xml:"-"`
+	EndTime   time.Time `xml:"-"`
+}
+
+// Duration returns the time the operation took to complete. It returns
+// false if the operation has not completed yet, or its status does not
+// report both its start and end times.
+func (op GetOperationStatusResponse) Duration() (time.Duration, bool) {
+	if !op.Status.IsTerminal() || op.StartTime.IsZero() || op.EndTime.IsZero() || op.EndTime.Before(op.StartTime) {
+		return 0, false
+	}
+	return op.EndTime.Sub(op.StartTime), true
 }
 
 // OperationStatus describes the states an Microsoft Azure Service Management
@@ -34,59 +72,420 @@ const (
 	OperationStatusInProgress OperationStatus = "InProgress"
 	OperationStatusSucceeded  OperationStatus = "Succeeded"
 	OperationStatusFailed     OperationStatus = "Failed"
+
+	// OperationStatusUnknown stands for any status the API reports which is
+	// not one of the above.
+	OperationStatusUnknown OperationStatus = "Unknown"
 )
 
+// IsTerminal reports whether the operation has completed, either
+// successfully or not. An unknown status is not terminal.
+func (s OperationStatus) IsTerminal() bool {
+	return s == OperationStatusSucceeded || s == OperationStatusFailed
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, mapping the statuses
+// not known to this package to OperationStatusUnknown.
+func (s *OperationStatus) UnmarshalText(text []byte) error {
+	switch status := OperationStatus(text); status {
+	case OperationStatusInProgress, OperationStatusSucceeded, OperationStatusFailed:
+		*s = status
+	default:
+		*s = OperationStatusUnknown
+	}
+	return nil
+}
+
 // OperationID is assigned by Azure API and can be used to look up the status of
-// an operation
+// an operation. The classic Service Management APIs identify an operation by
+// the request ID of the request which started it (the x-ms-request-id
+// header), and its status is read from the operations resource of the
+// subscription. The ARM-style APIs instead return the absolute URL of the
+// status of the operation (the Azure-AsyncOperation header), which is then
+// used as the ID and polled verbatim.
+//
+// An OperationID is a plain string which stays valid across processes, so it
+// can be persisted, e.g. through MarshalText, and the wait for the operation
+// resumed later by any client of the same subscription. Once the API has
+// forgotten the operation, polling it fails with ErrOperationNotFound.
 type OperationID string
 
+// MarshalText implements encoding.TextMarshaler.
+func (id OperationID) MarshalText() ([]byte, error) {
+	return []byte(id), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It fails on an empty
+// ID, which cannot have been minted by the API.
+func (id *OperationID) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return errors.New("azure: empty operation ID")
+	}
+	*id = OperationID(text)
+	return nil
+}
+
+// AsyncOperation describes the asynchronous operation started by a request,
+// for both conventions of the API, see OperationID. The fields are empty
+// when the response does not carry the corresponding header.
+type AsyncOperation struct {
+	// RequestID is the x-ms-request-id of the response, which identifies
+	// the operation for the classic Service Management APIs.
+	RequestID OperationID
+
+	// StatusURL is the Azure-AsyncOperation URL of the status of an
+	// ARM-style operation, to poll with GetOperationStatusURL.
+	StatusURL string
+
+	// Location is the Location of a 202 Accepted response: the URL of the
+	// resource, or of the result, of the operation.
+	Location string
+
+	// CorrelationRequestID is the CorrelationRequestIDHeader of the
+	// response, tying the operation to the other requests of a deployment.
+	CorrelationRequestID string
+}
+
+// ID returns the ID to poll the status of the operation with, i.e. the
+// status URL if set, and the request ID otherwise. It is empty if the
+// request did not start an operation.
+func (op AsyncOperation) ID() OperationID {
+	if op.StatusURL != "" {
+		return OperationID(op.StatusURL)
+	}
+	return op.RequestID
+}
+
 func (c client) GetOperationStatus(operationID OperationID) (GetOperationStatusResponse, error) {
+	return c.getOperationStatus(context.Background(), operationID)
+}
+
+func (c client) GetOperationStatusURL(statusURL string) (GetOperationStatusResponse, error) {
+	if !isAbsoluteURL(statusURL) {
+		return GetOperationStatusResponse{}, fmt.Errorf("azure: operation status URL %q is not absolute", statusURL)
+	}
+	return c.getOperationStatus(context.Background(), OperationID(statusURL))
+}
+
+func (c client) IsOperationComplete(operationID OperationID) (bool, error) {
+	op, err := c.getOperationStatus(context.Background(), operationID)
+	if err != nil {
+		return false, err
+	}
+	if !op.Status.IsTerminal() {
+		return false, nil
+	}
+	return true, operationFailure(operationID, op)
+}
+
+func (c client) OperationStatusURL(operationID OperationID) string {
+	if operationID == "" {
+		return ""
+	}
+	return c.createAzureRequestURI(operationStatusPath(operationID))
+}
+
+// operationStatusPath returns the URL, relative to the subscription unless
+// it is a status URL, of the status of the operation.
+func operationStatusPath(operationID OperationID) string {
+	if url := string(operationID); isAbsoluteURL(url) {
+		return url
+	}
+	return fmt.Sprintf("operations/%s", operationID)
+}
+
+func (c client) getOperationStatus(ctx context.Context, operationID OperationID) (GetOperationStatusResponse, error) {
 	operation := GetOperationStatusResponse{}
 	if operationID == "" {
 		return operation, fmt.Errorf(errParamNotSpecified, "operationID")
 	}
 
-	url := fmt.Sprintf("operations/%s", operationID)
-	response, azureErr := c.SendAzureGetRequest(url)
-	if azureErr != nil {
-		return operation, azureErr
+	response, err := c.sendAzureGetRequest(ctx, operationStatusPath(operationID))
+	if azureErr, ok := err.(AzureError); ok && azureErr.StatusCode == http.StatusNotFound {
+		return operation, ErrOperationNotFound
+	}
+	if err != nil {
+		return operation, err
 	}
 
-	err := xml.Unmarshal(response, &operation)
+	if body := bytes.TrimSpace(response); len(body) != 0 && body[0] == '{' {
+		return decodeAsyncOperationStatus(operationID, body)
+	}
+	if err := xml.Unmarshal(response, &operation); err != nil {
+		return operation, err
+	}
+	operation.PercentComplete, err = getOperationProgress(response)
+	if err != nil {
+		return operation, err
+	}
+	operation.StartTime, operation.EndTime, err = getOperationTimes(response)
 	return operation, err
 }
 
+// decodeAsyncOperationStatus decodes the JSON status of an ARM-style
+// asynchronous operation. A cancelled operation is reported as failed.
+func decodeAsyncOperationStatus(operationID OperationID, body []byte) (GetOperationStatusResponse, error) {
+	var status struct {
+		Status          string
+		PercentComplete *float64
+		StartTime       string
+		EndTime         string
+		Error           *struct {
+			Code    string
+			Message string
+		}
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return GetOperationStatusResponse{}, err
+	}
+
+	operation := GetOperationStatusResponse{ID: string(operationID)}
+	switch status.Status {
+	case "Canceled":
+		operation.Status = OperationStatusFailed
+		if status.Error == nil {
+			operation.Error = &AzureError{Code: "Canceled", Message: "the operation was cancelled"}
+		}
+	default:
+		if err := operation.Status.UnmarshalText([]byte(status.Status)); err != nil {
+			return operation, err
+		}
+	}
+	if status.Error != nil {
+		operation.Error = &AzureError{Code: status.Error.Code, Message: status.Error.Message}
+	}
+	if status.PercentComplete != nil {
+		percent := int(*status.PercentComplete)
+		operation.PercentComplete = &percent
+	}
+	operation.StartTime = parseOperationTime(status.StartTime)
+	operation.EndTime = parseOperationTime(status.EndTime)
+	return operation, nil
+}
+
+// isAbsoluteURL reports whether url is an absolute HTTP(S) URL, rather than
+// a path relative to the subscription.
+func isAbsoluteURL(url string) bool {
+	return strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "http://")
+}
+
+// getOperationProgress extracts the optional completion percentage from
+// an operation status response body.
+func getOperationProgress(response []byte) (*int, error) {
+	var progress struct {
+		PercentComplete *int
+		Progress        *int
+	}
+	if err := xml.Unmarshal(response, &progress); err != nil {
+		return nil, err
+	}
+	if progress.PercentComplete != nil {
+		return progress.PercentComplete, nil
+	}
+	return progress.Progress, nil
+}
+
+// getOperationTimes extracts the optional start and end times from an
+// operation status response body.
+func getOperationTimes(response []byte) (start, end time.Time, err error) {
+	var times struct {
+		StartTime string
+		EndTime   string
+	}
+	if err := xml.Unmarshal(response, &times); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return parseOperationTime(times.StartTime), parseOperationTime(times.EndTime), nil
+}
+
+// parseOperationTime parses a time reported in an operation status, which
+// is zero if empty or invalid: a malformed time must not fail the polling.
+func parseOperationTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
 func (c client) WaitForOperation(operationID OperationID, cancel chan struct{}) error {
+	return c.waitForOperation(context.Background(), operationID, nil, nil, cancel)
+}
+
+func (c client) WaitForOperationProgress(operationID OperationID, onProgress func(percent int), cancel chan struct{}) error {
+	onPoll := func(op GetOperationStatusResponse) {
+		if op.PercentComplete != nil && onProgress != nil {
+			onProgress(*op.PercentComplete)
+		}
+	}
+	return c.waitForOperation(context.Background(), operationID, onPoll, nil, cancel)
+}
+
+func (c client) WaitForOperationFunc(operationID OperationID, onPoll func(status GetOperationStatusResponse), cancel chan struct{}) error {
+	onPending := func(op GetOperationStatusResponse) {
+		if !op.Status.IsTerminal() && onPoll != nil {
+			onPoll(op)
+		}
+	}
+	return c.waitForOperation(context.Background(), operationID, onPending, nil, cancel)
+}
+
+func (c client) WaitForOperationUntil(operationID OperationID, done func(status GetOperationStatusResponse) bool, cancel chan struct{}) error {
+	return c.waitForOperation(context.Background(), operationID, nil, done, cancel)
+}
+
+func (c client) StartWaitForOperation(operationID OperationID) (<-chan error, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	// The poll is tracked before the goroutine starts, so that a Shutdown
+	// right after this returns waits for it.
+	ctx, release, err := c.trackPoll(ctx, operationID)
+	if err != nil {
+		cancel()
+		done <- err
+		return done, func() {}
+	}
+	go func() {
+		defer release()
+		err := c.pollOperation(ctx, operationID, nil, nil, nil)
+		if err == context.Canceled {
+			err = ErrOperationCancelled
+		}
+		done <- err
+	}()
+	return done, cancel
+}
+
+// waitForOperation polls for the status of the given operation until it
+// completes, the polling is cancelled, ctx is done or the client is shut
+// down. If onPoll is non-nil, it is called with every status received from
+// the API. If until is non-nil, the polling also stops, successfully, as soon
+// as it reports true for a status which is not terminal.
+func (c client) waitForOperation(ctx context.Context, operationID OperationID, onPoll func(GetOperationStatusResponse), until func(GetOperationStatusResponse) bool, cancel chan struct{}) error {
+	ctx, release, err := c.trackPoll(ctx, operationID)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return c.pollOperation(ctx, operationID, onPoll, until, cancel)
+}
+
+// pollOperation implements waitForOperation for an already tracked poll. It
+// returns ErrClientClosed if ctx was cancelled by Shutdown.
+func (c client) pollOperation(ctx context.Context, operationID OperationID, onPoll func(GetOperationStatusResponse), until func(GetOperationStatusResponse) bool, cancel chan struct{}) (err error) {
+	defer func() {
+		if errors.Is(err, ErrClientClosed) || err != nil && context.Cause(ctx) == ErrClientClosed {
+			err = ErrClientClosed
+		} else if err != nil && context.Cause(ctx) == ErrOperationCancelled {
+			err = ErrOperationCancelled
+		}
+	}()
+	var failures int
 	for {
-		done, err := c.checkOperationStatus(operationID)
-		if err != nil || done {
+		done, err := c.checkOperationStatus(ctx, operationID, onPoll, until)
+		if done {
+			// A terminal status wins over a concurrent cancellation.
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		interval := c.config.OperationPollInterval
+		switch {
+		case err != nil && isTransientPollError(err) && failures < c.config.OperationPollMaxErrors:
+			// Back off on top of the interval, as the request itself has
+			// already been retried.
+			interval += c.backoff(failures)
+			failures++
+			c.infof("azure: polling operation %s failed %d time(s) in a row, retrying in %v: %v", operationID, failures, interval, err)
+		case err != nil:
 			return err
+		default:
+			failures = 0
 		}
+		timer := time.NewTimer(pollDelay(ctx, interval))
 		select {
-		case <-time.After(c.config.OperationPollInterval):
+		case <-timer.C:
+			// The timer and the cancellation may be ready at once, in
+			// which case select picks either: cancelling must win.
+			select {
+			case <-cancel:
+				return ErrOperationCancelled
+			default:
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 		case <-cancel:
+			timer.Stop()
 			return ErrOperationCancelled
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
 		}
 	}
 }
 
-func (c client) checkOperationStatus(id OperationID) (done bool, err error) {
-	op, err := c.GetOperationStatus(id)
-	if err != nil {
-		return false, fmt.Errorf("Failed to get operation status '%s': %v", id, err)
+// isTransientPollError reports whether a failure to get the status of an
+// operation may go away on its own: a network failure or a server-side
+// error. Authentication failures, certificate errors and unknown operations
+// are not transient.
+func isTransientPollError(err error) bool {
+	if err == ErrOperationNotFound || isCertificateError(err) {
+		return false
+	}
+	var azureErr AzureError
+	if errors.As(err, &azureErr) {
+		switch azureErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests:
+			return true
+		}
+		return azureErr.StatusCode >= http.StatusInternalServerError
 	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
 
-	switch op.Status {
-	case OperationStatusSucceeded:
-		return true, nil
-	case OperationStatusFailed:
-		if op.Error != nil {
-			return true, op.Error
+// pollDelay returns the time to wait before the next status poll: the poll
+// interval, shortened to the time remaining until the deadline of ctx, so
+// that the loop never sleeps past it.
+func pollDelay(ctx context.Context, interval time.Duration) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < interval {
+			return remaining
 		}
-		return true, fmt.Errorf("Azure Operation (x-ms-request-id=%s) has failed", id)
-	case OperationStatusInProgress:
-		return false, nil
-	default:
-		return false, fmt.Errorf("Unknown operation status returned from API: %s (x-ms-request-id=%s)", op.Status, id)
 	}
+	return interval
+}
+
+func (c client) checkOperationStatus(ctx context.Context, id OperationID, onPoll func(GetOperationStatusResponse), until func(GetOperationStatusResponse) bool) (done bool, err error) {
+	op, err := c.getOperationStatus(ctx, id)
+	if err == ErrOperationNotFound {
+		return false, err
+	}
+	if err != nil {
+		return false, fmt.Errorf("Failed to get operation status '%s': %w", id, err)
+	}
+	if onPoll != nil {
+		onPoll(op)
+	}
+
+	if op.Status.IsTerminal() {
+		return true, operationFailure(id, op)
+	}
+	// InProgress, or a status unknown to this package: keep polling,
+	// unless the caller is satisfied with it.
+	return until != nil && until(op), nil
+}
+
+// operationFailure returns the error a terminal operation failed with, or
+// nil if it succeeded.
+func operationFailure(id OperationID, op GetOperationStatusResponse) error {
+	if op.Status != OperationStatusFailed {
+		return nil
+	}
+	if op.Error != nil {
+		return op.Error
+	}
+	return fmt.Errorf("Azure Operation (x-ms-request-id=%s) has failed", id)
 }
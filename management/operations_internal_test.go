@@ -0,0 +1,22 @@
+package management
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPollDelay(t *testing.T) {
+	if got := pollDelay(context.Background(), time.Minute); got != time.Minute {
+		t.Fatalf("pollDelay()=%v without deadline, want %v", got, time.Minute)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if got := pollDelay(ctx, time.Minute); got > time.Second || got <= 0 {
+		t.Fatalf("pollDelay()=%v with deadline in 1s, want at most 1s", got)
+	}
+	if got := pollDelay(ctx, time.Millisecond); got != time.Millisecond {
+		t.Fatalf("pollDelay()=%v with distant deadline, want %v", got, time.Millisecond)
+	}
+}
@@ -0,0 +1,728 @@
+package management_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/management"
+)
+
+const operationStatusFormat = `<Operation xmlns="http://schemas.microsoft.com/windowsazure">
+  <ID>%s</ID>
+  <Status>%s</Status>
+  <HttpStatusCode>200</HttpStatusCode>%s
+</Operation>`
+
+// operationStatusHandler returns a handler replying with the given sequence
+// of operation status documents, repeating the last one once exhausted.
+func operationStatusHandler(statuses ...string) http.Handler {
+	var n int
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[len(statuses)-1]
+		if n < len(statuses) {
+			status = statuses[n]
+		}
+		n++
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, status)
+	})
+}
+
+func operationStatus(status, extra string) string {
+	return fmt.Sprintf(operationStatusFormat, "op", status, extra)
+}
+
+func TestWaitForOperationProgress(t *testing.T) {
+	client := newTestClient(t, operationStatusHandler(
+		operationStatus("InProgress", ""),
+		operationStatus("InProgress", "<PercentComplete>40</PercentComplete>"),
+		operationStatus("InProgress", "<Progress>80</Progress>"),
+		operationStatus("Succeeded", "<PercentComplete>100</PercentComplete>"),
+	))
+
+	var progress []int
+	onProgress := func(percent int) {
+		progress = append(progress, percent)
+	}
+	if err := client.WaitForOperationProgress("op", onProgress, nil); err != nil {
+		t.Fatalf("WaitForOperationProgress()=%v", err)
+	}
+	if want := []int{40, 80, 100}; !reflect.DeepEqual(progress, want) {
+		t.Fatalf("got progress %v, want %v", progress, want)
+	}
+}
+
+func TestOperationDuration(t *testing.T) {
+	testCases := []struct {
+		status string
+		want   time.Duration
+		wantOK bool
+	}{
+		{operationStatus("Succeeded", "<StartTime>2017-03-01T10:00:00Z</StartTime><EndTime>2017-03-01T10:02:30.5Z</EndTime>"), 150500 * time.Millisecond, true},
+		{operationStatus("Failed", "<StartTime>2017-03-01T10:00:00+01:00</StartTime><EndTime>2017-03-01T09:00:01Z</EndTime>"), time.Second, true},
+		{operationStatus("InProgress", "<StartTime>2017-03-01T10:00:00Z</StartTime>"), 0, false},
+		{operationStatus("Succeeded", "<StartTime>2017-03-01T10:00:00Z</StartTime><EndTime></EndTime>"), 0, false},
+		{operationStatus("Succeeded", "<StartTime>yesterday</StartTime><EndTime>2017-03-01T10:00:00Z</EndTime>"), 0, false},
+		{operationStatus("Succeeded", ""), 0, false},
+	}
+	for i, testCase := range testCases {
+		client := newTestClient(t, operationStatusHandler(testCase.status))
+		op, err := client.GetOperationStatus("id")
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if got, ok := op.Duration(); got != testCase.want || ok != testCase.wantOK {
+			t.Errorf("%d: Duration()=%v, %t, want %v, %t", i, got, ok, testCase.want, testCase.wantOK)
+		}
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"Succeeded","startTime":"2017-03-01T10:00:00.1234567Z","endTime":"2017-03-01T10:00:05.1234567Z"}`)
+	}))
+	defer srv.Close()
+	client := newTestClientFromConfig(t, newTestConfig(srv.URL))
+	op, err := client.GetOperationStatusURL(srv.URL + "/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := op.Duration(); got != 5*time.Second || !ok {
+		t.Fatalf("Duration()=%v, %t, want %v", got, ok, 5*time.Second)
+	}
+}
+
+// asyncHandler serves a long running operation: requests to the resource
+// start the operation with ID "op", whose status is served by ops.
+func asyncHandler(ops http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/"+testSubscriptionID+"/operations/", ops)
+	mux.HandleFunc("/"+testSubscriptionID+"/resource", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ms-request-id", "op")
+		w.WriteHeader(http.StatusAccepted)
+	})
+	return mux
+}
+
+func TestDeleteAndWait(t *testing.T) {
+	client := newTestClient(t, asyncHandler(operationStatusHandler(
+		operationStatus("InProgress", ""),
+		operationStatus("Succeeded", ""),
+	)))
+	if err := client.DeleteAndWait(context.Background(), "resource"); err != nil {
+		t.Fatalf("DeleteAndWait()=%v", err)
+	}
+}
+
+func TestDeleteAndWaitNotFound(t *testing.T) {
+	for i, body := range []string{
+		`<Error><Code>ResourceNotFound</Code><Message>gone</Message></Error>`,
+		`<Error><Code>HostedServiceNotFound</Code><Message>gone</Message></Error>`,
+		"",
+	} {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, body)
+		}))
+		if err := client.DeleteAndWait(context.Background(), "resource"); err != nil {
+			t.Fatalf("Test %d: DeleteAndWait()=%v", i+1, err)
+		}
+	}
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	}))
+	err := client.DeleteAndWait(context.Background(), "resource")
+	if azureErr, ok := err.(management.AzureError); !ok || azureErr.StatusCode != http.StatusConflict {
+		t.Fatalf("DeleteAndWait()=%v, want the 409 AzureError", err)
+	}
+}
+
+func TestPutAndWaitCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	client := newTestClient(t, asyncHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		fmt.Fprint(w, operationStatus("InProgress", ""))
+	})))
+	if err := client.PutAndWait(ctx, "resource", "", []byte("<Resource/>")); err != context.Canceled {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestWaitForOperationNotFound(t *testing.T) {
+	var requests int
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `<Error><Code>ResourceNotFound</Code><Message>The operation request ID was not found.</Message></Error>`)
+	}))
+
+	if err := client.WaitForOperation("op", nil); err != management.ErrOperationNotFound {
+		t.Fatalf("got error %v, want %v", err, management.ErrOperationNotFound)
+	}
+	if requests != 1 {
+		t.Fatalf("got %d requests, want 1", requests)
+	}
+}
+
+func TestCreateOrUpdateAndWaitCancelledInFlight(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	release := make(chan struct{})
+	defer close(release)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	if err := client.CreateOrUpdateAndWait(ctx, "resource", "", []byte("<Resource/>")); err != context.DeadlineExceeded {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestCreateOrUpdateWithRollback(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	release := make(chan struct{})
+	defer close(release)
+	deleted := make(chan string, 1)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PUT":
+			<-release
+		case "DELETE":
+			deleted <- r.URL.Path
+		}
+	}))
+
+	rolledBack, err := client.CreateOrUpdateWithRollback(ctx, "resource", "", []byte("<Resource/>"))
+	if err != context.DeadlineExceeded || !rolledBack {
+		t.Fatalf("got (%t, %v), want (true, %v)", rolledBack, err, context.DeadlineExceeded)
+	}
+	if path, want := <-deleted, "/"+testSubscriptionID+"/resource"; path != want {
+		t.Fatalf("got DELETE %s, want %s", path, want)
+	}
+}
+
+func TestCreateOrUpdateWithRollbackNotNeeded(t *testing.T) {
+	var methods []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `<Error><Code>ConflictError</Code><Message>exists</Message></Error>`)
+	}))
+
+	rolledBack, err := client.CreateOrUpdateWithRollback(context.Background(), "resource", "", []byte("<Resource/>"))
+	if err == nil || rolledBack {
+		t.Fatalf("got (%t, %v), want (false, a conflict error)", rolledBack, err)
+	}
+	if !reflect.DeepEqual(methods, []string{"PUT"}) {
+		t.Fatalf("got requests %v, want a single PUT", methods)
+	}
+}
+
+func TestStartWaitForOperation(t *testing.T) {
+	client := newTestClient(t, operationStatusHandler(
+		operationStatus("InProgress", ""),
+		operationStatus("Succeeded", ""),
+	))
+	done, cancel := client.StartWaitForOperation("id")
+	defer cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("StartWaitForOperation()=%v", err)
+	}
+}
+
+func TestStartWaitForOperationCancel(t *testing.T) {
+	client := newTestClient(t, operationStatusHandler(operationStatus("InProgress", "")))
+	done, cancel := client.StartWaitForOperation("id")
+	cancel()
+	cancel()
+	if err := <-done; err != management.ErrOperationCancelled {
+		t.Fatalf("got error %v, want %v", err, management.ErrOperationCancelled)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	client := newTestClient(t, operationStatusHandler(operationStatus("InProgress", "")))
+	var pollers []<-chan error
+	for i := 0; i < 3; i++ {
+		done, cancel := client.StartWaitForOperation("id")
+		defer cancel()
+		pollers = append(pollers, done)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown()=%v", err)
+	}
+	for i, done := range pollers {
+		select {
+		case err := <-done:
+			if err != management.ErrClientClosed {
+				t.Fatalf("poll %d: got error %v, want %v", i, err, management.ErrClientClosed)
+			}
+		default:
+			t.Fatalf("poll %d is still running after Shutdown", i)
+		}
+	}
+
+	if _, err := client.SendAzureGetRequest("resource"); err != management.ErrClientClosed {
+		t.Fatalf("SendAzureGetRequest() after Shutdown: got error %v, want %v", err, management.ErrClientClosed)
+	}
+	if err := client.WaitForOperation("id", nil); err != management.ErrClientClosed {
+		t.Fatalf("WaitForOperation() after Shutdown: got error %v, want %v", err, management.ErrClientClosed)
+	}
+	if err := client.Shutdown(ctx); err != nil {
+		t.Fatalf("second Shutdown()=%v", err)
+	}
+}
+
+func TestCancelAllOperations(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	polled := make(chan struct{}, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		id := path.Base(r.URL.Path)
+		requests[id]++
+		n := requests[id]
+		mu.Unlock()
+		status := "InProgress"
+		if id == "done" && n > 1 {
+			status = "Succeeded"
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintf(w, operationStatusFormat, id, status, "")
+		if n == 1 || id == "cancellable" && n == 2 {
+			polled <- struct{}{}
+		}
+	}))
+	defer srv.Close()
+
+	config := newTestConfig(srv.URL)
+	config.OperationPollInterval = time.Hour
+	var cancelled []management.OperationID
+	config.CancelOperation = func(ctx context.Context, client management.Client, id management.OperationID) error {
+		if id != "cancellable" {
+			return management.ErrCancelNotSupported
+		}
+		cancelled = append(cancelled, id)
+		return nil
+	}
+	client := newTestClientFromConfig(t, config)
+	if result, err := client.CancelAllOperations(context.Background()); err != nil || !reflect.DeepEqual(result, management.CancelResult{}) {
+		t.Fatalf("CancelAllOperations() with no operation: got %+v, %v", result, err)
+	}
+
+	var pollers []<-chan error
+	for _, id := range []management.OperationID{"cancellable", "cancellable", "done", "running"} {
+		done, cancel := client.StartWaitForOperation(id)
+		defer cancel()
+		pollers = append(pollers, done)
+	}
+	// Each poll has fetched the status once, and sleeps until the next.
+	for range pollers {
+		<-polled
+	}
+
+	result, err := client.CancelAllOperations(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := management.CancelResult{
+		Cancelled: []management.OperationID{"cancellable"},
+		Completed: []management.OperationID{"done"},
+		Stopped:   []management.OperationID{"running"},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Fatalf("CancelAllOperations(): got %+v, want %+v", result, want)
+	}
+	if !reflect.DeepEqual(cancelled, want.Cancelled) {
+		t.Fatalf("CancelOperation called for %v, want %v", cancelled, want.Cancelled)
+	}
+	for _, i := range []int{0, 1, 3} {
+		select {
+		case err := <-pollers[i]:
+			if err != management.ErrOperationCancelled {
+				t.Fatalf("poll %d: got error %v, want %v", i, err, management.ErrOperationCancelled)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("poll %d is still running after CancelAllOperations", i)
+		}
+	}
+}
+
+func TestWaitForOperationNetError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	client := newTestClientFromConfig(t, newTestConfig(srv.URL))
+	err := client.WaitForOperation("op", nil)
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		t.Fatalf("got error %v (%T), want it to wrap a *net.OpError", err, err)
+	}
+}
+
+func TestWaitForOperationFunc(t *testing.T) {
+	client := newTestClient(t, operationStatusHandler(
+		operationStatus("InProgress", ""),
+		operationStatus("InProgress", ""),
+		operationStatus("Succeeded", ""),
+	))
+	var polls []management.OperationStatus
+	onPoll := func(op management.GetOperationStatusResponse) {
+		polls = append(polls, op.Status)
+	}
+	if err := client.WaitForOperationFunc("id", onPoll, nil); err != nil {
+		t.Fatalf("WaitForOperationFunc()=%v", err)
+	}
+	want := []management.OperationStatus{management.OperationStatusInProgress, management.OperationStatusInProgress}
+	if !reflect.DeepEqual(polls, want) {
+		t.Fatalf("got polls %v, want %v", polls, want)
+	}
+}
+
+func TestWaitForOperationUntil(t *testing.T) {
+	client := newTestClient(t, operationStatusHandler(
+		operationStatus("InProgress", ""),
+		operationStatus("InProgress", "<PercentComplete>60</PercentComplete>"),
+		operationStatus("InProgress", "<PercentComplete>80</PercentComplete>"),
+		operationStatus("Succeeded", ""),
+	))
+	var polls int
+	done := func(op management.GetOperationStatusResponse) bool {
+		polls++
+		return op.PercentComplete != nil && *op.PercentComplete >= 50
+	}
+	if err := client.WaitForOperationUntil("id", done, nil); err != nil {
+		t.Fatalf("WaitForOperationUntil()=%v", err)
+	}
+	if polls != 2 {
+		t.Fatalf("got %d polls, want to stop at the second", polls)
+	}
+
+	// A terminal failure is reported whatever the predicate says.
+	failure := operationStatus("Failed", "<Error><Code>Conflict</Code><Message>taken</Message></Error>")
+	client = newTestClient(t, operationStatusHandler(failure))
+	never := func(management.GetOperationStatusResponse) bool { return false }
+	if err := client.WaitForOperationUntil("id", never, nil); err == nil {
+		t.Fatal("WaitForOperationUntil() of a failed operation succeeded")
+	}
+}
+
+func TestIsOperationComplete(t *testing.T) {
+	failure := operationStatus("Failed", "<Error><Code>Conflict</Code><Message>taken</Message></Error>")
+	testCases := []struct {
+		status   string
+		wantDone bool
+		wantErr  bool
+	}{
+		{operationStatus("InProgress", ""), false, false},
+		{operationStatus("Rebooting", ""), false, false},
+		{operationStatus("Succeeded", ""), true, false},
+		{failure, true, true},
+	}
+	for i, testCase := range testCases {
+		client := newTestClient(t, operationStatusHandler(testCase.status))
+		done, err := client.IsOperationComplete("id")
+		if done != testCase.wantDone || (err != nil) != testCase.wantErr {
+			t.Errorf("%d: IsOperationComplete()=%t, %v, want %t and error %t", i, done, err, testCase.wantDone, testCase.wantErr)
+		}
+	}
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `<Error><Code>ResourceNotFound</Code><Message>The operation request ID was not found.</Message></Error>`)
+	}))
+	if done, err := client.IsOperationComplete("id"); done || err != management.ErrOperationNotFound {
+		t.Fatalf("IsOperationComplete()=%t, %v, want %v", done, err, management.ErrOperationNotFound)
+	}
+}
+
+func TestOperationStatusUnknown(t *testing.T) {
+	client := newTestClient(t, operationStatusHandler(
+		operationStatus("Rebooting", ""),
+		operationStatus("Succeeded", ""),
+	))
+	op, err := client.GetOperationStatus("id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op.Status != management.OperationStatusUnknown || op.Status.IsTerminal() {
+		t.Fatalf("got status %q, want non-terminal %q", op.Status, management.OperationStatusUnknown)
+	}
+	if err := client.WaitForOperation("id", nil); err != nil {
+		t.Fatalf("WaitForOperation()=%v", err)
+	}
+
+	// The polls of an unknown status keep the heartbeat going.
+	client = newTestClient(t, operationStatusHandler(
+		operationStatus("InProgress", ""),
+		operationStatus("Rebooting", ""),
+		operationStatus("Succeeded", ""),
+	))
+	var polls []management.OperationStatus
+	onPoll := func(op management.GetOperationStatusResponse) {
+		polls = append(polls, op.Status)
+	}
+	if err := client.WaitForOperationFunc("id", onPoll, nil); err != nil {
+		t.Fatalf("WaitForOperationFunc()=%v", err)
+	}
+	want := []management.OperationStatus{management.OperationStatusInProgress, management.OperationStatusUnknown}
+	if !reflect.DeepEqual(polls, want) {
+		t.Fatalf("got polls %v, want %v", polls, want)
+	}
+}
+
+func TestAsyncOperationStatusURL(t *testing.T) {
+	testCases := []struct {
+		final   string
+		wantErr bool
+	}{
+		{`{"status":"Succeeded"}`, false},
+		{`{"status":"Failed","error":{"code":"Conflict","message":"busy"}}`, true},
+		{`{"status":"Canceled"}`, true},
+	}
+	for i, testCase := range testCases {
+		var srv *httptest.Server
+		var polls int
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/status/1":
+				if polls++; polls == 1 {
+					fmt.Fprint(w, `{"status":"InProgress"}`)
+					return
+				}
+				fmt.Fprint(w, testCase.final)
+			default:
+				w.Header().Set("x-ms-request-id", "ignored")
+				w.Header().Set("Azure-AsyncOperation", srv.URL+"/status/1")
+				w.WriteHeader(http.StatusAccepted)
+			}
+		}))
+		client := newTestClientFromConfig(t, newTestConfig(srv.URL))
+
+		err := client.PutAndWait(context.Background(), "resource", "", []byte("<Resource/>"))
+		srv.Close()
+		if (err != nil) != testCase.wantErr {
+			t.Fatalf("Test %d: got error %v, want error %t", i+1, err, testCase.wantErr)
+		}
+		if polls != 2 {
+			t.Fatalf("Test %d: got %d polls of the status URL, want 2", i+1, polls)
+		}
+	}
+}
+
+func TestSendAzurePostRequestAsync(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/status/1":
+			fmt.Fprint(w, `{"status":"Succeeded"}`)
+		case "/" + testSubscriptionID + "/arm":
+			w.Header().Set("x-ms-request-id", "request-1")
+			w.Header().Set("Azure-AsyncOperation", srv.URL+"/status/1")
+			w.Header().Set("Location", srv.URL+"/result/1")
+			w.WriteHeader(http.StatusAccepted)
+		case "/" + testSubscriptionID + "/rdfe":
+			w.Header().Set("x-ms-request-id", "op")
+			w.WriteHeader(http.StatusAccepted)
+		case "/" + testSubscriptionID + "/operations/op":
+			fmt.Fprint(w, operationStatus("Succeeded", ""))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	client := newTestClientFromConfig(t, newTestConfig(srv.URL))
+
+	arm, err := client.SendAzurePostRequestAsync("arm", []byte("<Request/>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := management.AsyncOperation{RequestID: "request-1", StatusURL: srv.URL + "/status/1", Location: srv.URL + "/result/1"}
+	if arm != want || arm.ID() != management.OperationID(want.StatusURL) {
+		t.Fatalf("got %+v (ID %s), want %+v polled by its status URL", arm, arm.ID(), want)
+	}
+	rdfe, err := client.SendAzurePostRequestAsync("rdfe", []byte("<Request/>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rdfe.ID() != "op" {
+		t.Fatalf("got ID %q, want the request ID", rdfe.ID())
+	}
+
+	for _, op := range []management.AsyncOperation{arm, rdfe} {
+		if err := client.WaitForOperation(op.ID(), nil); err != nil {
+			t.Fatalf("WaitForOperation(%s)=%v", op.ID(), err)
+		}
+	}
+}
+
+func TestGetOperationStatusURLNotAbsolute(t *testing.T) {
+	client := newTestClient(t, http.NotFoundHandler())
+	if _, err := client.GetOperationStatusURL("operations/id"); err == nil {
+		t.Fatal("GetOperationStatusURL() of a relative URL succeeded")
+	}
+}
+
+func TestOperationStatusURL(t *testing.T) {
+	var requested string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = "http://" + r.Host + r.URL.RequestURI()
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, operationStatus("Succeeded", ""))
+	}))
+	defer srv.Close()
+	client := newTestClientFromConfig(t, newTestConfig(srv.URL))
+
+	for _, id := range []management.OperationID{"id", management.OperationID(srv.URL + "/status/id?api-version=2016-06-01")} {
+		if _, err := client.GetOperationStatus(id); err != nil {
+			t.Fatal(err)
+		}
+		if got := client.OperationStatusURL(id); got != requested {
+			t.Fatalf("OperationStatusURL(%q)=%q, want the requested %q", id, got, requested)
+		}
+	}
+	if got := client.OperationStatusURL(""); got != "" {
+		t.Fatalf("OperationStatusURL(\"\")=%q, want empty", got)
+	}
+}
+
+func TestResumeWaitForOperation(t *testing.T) {
+	var polled []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + testSubscriptionID + "/resource":
+			w.Header().Set("x-ms-request-id", "op-1")
+		case "/" + testSubscriptionID + "/operations/op-1":
+			polled = append(polled, "op-1")
+			fmt.Fprint(w, operationStatus("Succeeded", ""))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<Error><Code>ResourceNotFound</Code><Message>The operation request ID was not found.</Message></Error>`)
+		}
+	}))
+	defer srv.Close()
+
+	// The first process starts the operation and persists its ID.
+	id, err := newTestClientFromConfig(t, newTestConfig(srv.URL)).SendAzurePutRequest("resource", "", []byte("<Resource/>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := json.Marshal(struct{ Operation management.OperationID }{id})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The second one restores it and resumes waiting with a new client.
+	var restored struct{ Operation management.OperationID }
+	if err := json.Unmarshal(state, &restored); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Operation != id {
+		t.Fatalf("got operation ID %q after a round-trip, want %q", restored.Operation, id)
+	}
+	client := newTestClientFromConfig(t, newTestConfig(srv.URL))
+	if err := client.WaitForOperation(restored.Operation, nil); err != nil {
+		t.Fatalf("WaitForOperation()=%v", err)
+	}
+	if !reflect.DeepEqual(polled, []string{"op-1"}) {
+		t.Fatalf("got polls %v, want a single one of op-1", polled)
+	}
+
+	// An operation the API has forgotten is reported as not found.
+	var expired management.OperationID
+	if err := expired.UnmarshalText([]byte("op-0")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.WaitForOperation(expired, nil); err != management.ErrOperationNotFound {
+		t.Fatalf("got error %v, want %v", err, management.ErrOperationNotFound)
+	}
+	if err := expired.UnmarshalText(nil); err == nil {
+		t.Fatal("UnmarshalText() of an empty ID succeeded")
+	}
+}
+
+func TestWaitForOperationTransientErrors(t *testing.T) {
+	const unavailable = "503"
+	testCases := []struct {
+		responses []string
+		wantErr   bool
+	}{
+		{[]string{unavailable, unavailable, "InProgress", unavailable, "Succeeded"}, false},
+		{[]string{unavailable, unavailable, unavailable, "Succeeded"}, true},
+		{[]string{"403", "Succeeded"}, true},
+	}
+	for i, testCase := range testCases {
+		var polls int
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			response := testCase.responses[polls]
+			polls++
+			switch response {
+			case unavailable:
+				w.WriteHeader(http.StatusServiceUnavailable)
+				fmt.Fprint(w, `<Error><Code>ServiceUnavailable</Code><Message>busy</Message></Error>`)
+			case "403":
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `<Error><Code>ForbiddenError</Code><Message>denied</Message></Error>`)
+			default:
+				fmt.Fprint(w, operationStatus(response, ""))
+			}
+		}))
+		config := newTestConfig(srv.URL)
+		config.OperationPollMaxErrors = 2
+		config.RetryPolicy = management.DefaultRetryPolicy{MaxRetries: 0}
+		client := newTestClientFromConfig(t, config)
+
+		err := client.WaitForOperation("op", nil)
+		srv.Close()
+		if (err != nil) != testCase.wantErr {
+			t.Fatalf("Test %d: got error %v after %d polls, want error %t", i+1, err, polls, testCase.wantErr)
+		}
+	}
+}
+
+func TestWaitForOperationCancelledOnSuccess(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		cancel := make(chan struct{})
+		var polls int
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if polls++; polls == 1 {
+				fmt.Fprint(w, operationStatus("InProgress", ""))
+				return
+			}
+			// Signal cancelling on the same poll the operation succeeds.
+			close(cancel)
+			fmt.Fprint(w, operationStatus("Succeeded", ""))
+		}))
+		if err := client.WaitForOperation("op", cancel); err != nil {
+			t.Fatalf("run %d: got error %v, want the success to win over the cancellation", i, err)
+		}
+	}
+}
+
+func TestWaitForOperationCancelledBeforePoll(t *testing.T) {
+	var polls int
+	cancel := make(chan struct{})
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		close(cancel)
+		fmt.Fprint(w, operationStatus("InProgress", ""))
+	}))
+	if err := client.WaitForOperation("op", cancel); err != management.ErrOperationCancelled {
+		t.Fatalf("got error %v, want %v", err, management.ErrOperationCancelled)
+	}
+	if polls != 1 {
+		t.Fatalf("got %d polls, want none after the cancellation", polls)
+	}
+}
@@ -0,0 +1,74 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// AuthError is returned by Ping when the management API rejects the
+// credentials of the client.
+type AuthError struct {
+	// StatusCode is either 401 Unauthorized or 403 Forbidden.
+	StatusCode int
+
+	// Err is the error returned by the API.
+	Err error
+}
+
+// Error implements the error interface for the AuthError type.
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("azure: credentials rejected (%d %s): %v", e.StatusCode, http.StatusText(e.StatusCode), e.Err)
+}
+
+// Unwrap returns the error returned by the API.
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// ConnectivityError is returned by Ping when the management API is not
+// usable for a reason other than the credentials: the request could not be
+// sent, e.g. because of a DNS or connection failure, or it failed with a
+// status other than 401 and 403, e.g. 404 for a wrong PingPath, 429 or 5xx.
+type ConnectivityError struct {
+	// Err is the transport error, or the AzureError of the failed response.
+	Err error
+}
+
+// Error implements the error interface for the ConnectivityError type.
+func (e *ConnectivityError) Error() string {
+	return fmt.Sprintf("azure: management API unreachable: %v", e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ConnectivityError) Unwrap() error {
+	return e.Err
+}
+
+func (client client) Ping(ctx context.Context) error {
+	uri := client.config.ManagementURL + "/" + client.publishSettings.SubscriptionID
+	if client.config.PingPath != "" {
+		uri += "/" + client.config.PingPath
+	}
+
+	// A health check reports the first failure rather than retrying it.
+	client.config.RetryPolicy = DefaultRetryPolicy{MaxRetries: 0}
+	response, err := client.sendAzureRequest(ctx, http.MethodGet, uri, "", nil)
+	if err == nil {
+		response.Body.Close()
+		return nil
+	}
+
+	var azureErr AzureError
+	var prepared *PreparedRequest
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case err == ErrClientClosed, err == ErrNoCredentials, errors.As(err, &prepared):
+		return err
+	case errors.As(err, &azureErr) && (azureErr.StatusCode == http.StatusUnauthorized || azureErr.StatusCode == http.StatusForbidden):
+		return &AuthError{StatusCode: azureErr.StatusCode, Err: err}
+	}
+	return &ConnectivityError{Err: err}
+}
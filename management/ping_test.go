@@ -0,0 +1,81 @@
+package management_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/management"
+)
+
+func TestPing(t *testing.T) {
+	var status, calls int
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		path = r.URL.Path
+		if r.URL.Path == "/moved" {
+			return
+		}
+		if status == http.StatusTemporaryRedirect {
+			w.Header().Set("Location", "/moved")
+		}
+		w.WriteHeader(status)
+		if status >= http.StatusBadRequest {
+			fmt.Fprint(w, `<Error><Code>ForbiddenError</Code><Message>denied</Message></Error>`)
+		}
+	}))
+	defer srv.Close()
+
+	config := newTestConfig(srv.URL)
+	config.PingPath = "locations"
+	client := newTestClientFromConfig(t, config)
+
+	status = http.StatusOK
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping()=%v", err)
+	}
+	if want := "/" + testSubscriptionID + "/locations"; path != want {
+		t.Fatalf("got path %q, want %q", path, want)
+	}
+
+	for _, status = range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		err := client.Ping(context.Background())
+		if authErr, ok := err.(*management.AuthError); !ok || authErr.StatusCode != status {
+			t.Fatalf("%d: got error %v (%T), want *management.AuthError", status, err, err)
+		}
+	}
+
+	// The other failure statuses mean the endpoint is not usable.
+	for _, status = range []int{http.StatusNotFound, http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		calls = 0
+		err := client.Ping(context.Background())
+		var connErr *management.ConnectivityError
+		var azureErr management.AzureError
+		if !errors.As(err, &connErr) || !errors.As(err, &azureErr) || azureErr.StatusCode != status {
+			t.Fatalf("%d: got error %v (%T), want a *management.ConnectivityError wrapping the AzureError", status, err, err)
+		}
+		if calls != 1 {
+			t.Fatalf("%d: got %d attempts, want the failure reported without retrying", status, calls)
+		}
+	}
+
+	// Redirects are followed like those of the other requests.
+	status = http.StatusTemporaryRedirect
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() of a redirected endpoint: %v", err)
+	}
+	if path != "/moved" {
+		t.Fatalf("got path %q, want the redirect followed", path)
+	}
+
+	srv.Close()
+	if err := client.Ping(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	} else if _, ok := err.(*management.ConnectivityError); !ok {
+		t.Fatalf("got error %v (%T), want *management.ConnectivityError", err, err)
+	}
+}
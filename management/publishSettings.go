@@ -1,9 +1,11 @@
 package management
 
 import (
+	"crypto/tls"
 	"encoding/base64"
 	"encoding/pem"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io/ioutil"
 
@@ -60,16 +62,11 @@ func ClientFromPublishSettingsDataWithConfig(data []byte, subscriptionID string,
 					return client, err
 				}
 
-				pems, err := pkcs12.ToPEM(pfxData, "")
+				cert, err := pfxToPEM(pfxData, "")
 				if err != nil {
 					return client, err
 				}
 
-				cert := []byte{}
-				for _, b := range pems {
-					cert = append(cert, pem.EncodeToMemory(b)...)
-				}
-
 				config.ManagementURL = sub.ServiceManagementURL
 				return makeClient(sub.ID, cert, config)
 			}
@@ -79,6 +76,42 @@ func ClientFromPublishSettingsDataWithConfig(data []byte, subscriptionID string,
 	return client, fmt.Errorf("could not find subscription '%s' in settings provided", subscriptionID)
 }
 
+// pfxToPEM decodes PKCS#12 data into PEM blocks accepted by makeClient. The
+// certificate matching the private key is placed first, so that it is used
+// as the leaf certificate regardless of its position in the PFX chain.
+func pfxToPEM(pfxData []byte, password string) ([]byte, error) {
+	blocks, err := pkcs12.ToPEM(pfxData, password)
+	if err != nil {
+		return nil, err
+	}
+
+	var key []byte
+	var certs [][]byte
+	for _, b := range blocks {
+		if b.Type == "CERTIFICATE" {
+			certs = append(certs, pem.EncodeToMemory(b))
+		} else if key == nil {
+			key = pem.EncodeToMemory(b)
+		}
+	}
+	if key == nil {
+		return nil, errors.New("azure: no private key found in PFX data")
+	}
+
+	for i, cert := range certs {
+		if _, err := tls.X509KeyPair(cert, key); err == nil {
+			certs[0], certs[i] = certs[i], certs[0]
+			break
+		}
+	}
+
+	out := key
+	for _, cert := range certs {
+		out = append(out, cert...)
+	}
+	return out, nil
+}
+
 type publishSettings struct {
 	SubscriptionID   string
 	SubscriptionCert []byte
@@ -0,0 +1,72 @@
+package management
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers reporting how many requests of the given kind remain before the
+// API starts throttling the caller.
+const (
+	rateLimitSubscriptionReadsHeader  = "x-ms-ratelimit-remaining-subscription-reads"
+	rateLimitSubscriptionWritesHeader = "x-ms-ratelimit-remaining-subscription-writes"
+	rateLimitTenantReadsHeader        = "x-ms-ratelimit-remaining-tenant-reads"
+	rateLimitTenantWritesHeader       = "x-ms-ratelimit-remaining-tenant-writes"
+)
+
+// RateLimit describes the throttling quotas remaining for the caller, as
+// reported by the API in the x-ms-ratelimit-remaining-* response headers.
+// A field is -1 when the corresponding header was not present.
+type RateLimit struct {
+	RemainingSubscriptionReads  int
+	RemainingSubscriptionWrites int
+	RemainingTenantReads        int
+	RemainingTenantWrites       int
+}
+
+// ParseRateLimit reads the rate limit headers. It returns false if none of
+// them is present or valid. It can be used for the responses received by
+// the clients of the ARM packages as well.
+func ParseRateLimit(h http.Header) (RateLimit, bool) {
+	var found bool
+	parse := func(header string) int {
+		n, err := strconv.Atoi(h.Get(header))
+		if err != nil {
+			return -1
+		}
+		found = true
+		return n
+	}
+
+	rl := RateLimit{
+		RemainingSubscriptionReads:  parse(rateLimitSubscriptionReadsHeader),
+		RemainingSubscriptionWrites: parse(rateLimitSubscriptionWritesHeader),
+		RemainingTenantReads:        parse(rateLimitTenantReadsHeader),
+		RemainingTenantWrites:       parse(rateLimitTenantWritesHeader),
+	}
+	return rl, found
+}
+
+func (client client) LastRateLimit() (RateLimit, bool) {
+	if client.state == nil {
+		return RateLimit{}, false
+	}
+	client.state.mu.Lock()
+	defer client.state.mu.Unlock()
+	return client.state.rateLimit, client.state.hasRateLimit
+}
+
+// recordResponse updates the state of the client with the information
+// carried by the response headers.
+func (client client) recordResponse(response *http.Response) {
+	if client.state == nil {
+		return
+	}
+	if rl, ok := ParseRateLimit(response.Header); ok {
+		client.state.mu.Lock()
+		client.state.rateLimit, client.state.hasRateLimit = rl, true
+		client.state.mu.Unlock()
+	}
+	client.recordServerTime(response.Header, time.Now())
+}
@@ -0,0 +1,52 @@
+package management_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/management"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	h := http.Header{}
+	if _, ok := management.ParseRateLimit(h); ok {
+		t.Fatal("expected no rate limit for empty headers")
+	}
+
+	h.Set("x-ms-ratelimit-remaining-subscription-reads", "11999")
+	h.Set("x-ms-ratelimit-remaining-subscription-writes", "garbage")
+	rl, ok := management.ParseRateLimit(h)
+	if !ok {
+		t.Fatal("expected rate limit to be parsed")
+	}
+	want := management.RateLimit{
+		RemainingSubscriptionReads:  11999,
+		RemainingSubscriptionWrites: -1,
+		RemainingTenantReads:        -1,
+		RemainingTenantWrites:       -1,
+	}
+	if rl != want {
+		t.Fatalf("got %+v, want %+v", rl, want)
+	}
+}
+
+func TestLastRateLimit(t *testing.T) {
+	var remaining = []string{"", "1199", ""}
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if v := remaining[0]; v != "" {
+			w.Header().Set("x-ms-ratelimit-remaining-subscription-writes", v)
+		}
+		remaining = remaining[1:]
+		w.Header().Set("x-ms-request-id", "op")
+	}))
+
+	for i, want := range []int{0, 1199, 1199} {
+		if _, err := client.SendAzureDeleteRequest("resource"); err != nil {
+			t.Fatal(err)
+		}
+		rl, ok := client.LastRateLimit()
+		if ok != (want != 0) || (ok && rl.RemainingSubscriptionWrites != want) {
+			t.Fatalf("Request %d: got %+v, %t, want %d remaining writes", i+1, rl, ok, want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package management
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+)
+
+// GetAzureResource gets the resource at url, like SendAzureGetRequest, and
+// decodes it into a T. The body is decoded as JSON or XML according to the
+// Content-Type of the response; when it is missing or names neither, e.g.
+// for the Client implementations other than the one of this package, the
+// format is told from the body itself.
+func GetAzureResource[T any](c Client, url string) (T, error) {
+	var (
+		resource    T
+		body        []byte
+		contentType string
+		err         error
+	)
+	if client, ok := c.(client); ok {
+		body, contentType, err = client.getResource(context.Background(), url)
+	} else {
+		body, err = c.SendAzureGetRequest(url)
+	}
+	if err != nil {
+		return resource, err
+	}
+	if isJSONResource(body, contentType) {
+		err = json.Unmarshal(body, &resource)
+	} else {
+		err = xml.Unmarshal(body, &resource)
+	}
+	return resource, err
+}
+
+// isJSONResource reports whether a resource with the given body and content
+// type is encoded as JSON rather than XML.
+func isJSONResource(body []byte, contentType string) bool {
+	switch contentType = strings.ToLower(contentType); {
+	case strings.Contains(contentType, "json"):
+		return true
+	case strings.Contains(contentType, "xml"):
+		return false
+	}
+	body = bytes.TrimSpace(body)
+	return len(body) != 0 && (body[0] == '{' || body[0] == '[')
+}
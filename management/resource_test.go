@@ -0,0 +1,54 @@
+package management_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/management"
+)
+
+type testResource struct {
+	Name  string `xml:"Name" json:"name"`
+	Count int    `xml:"Count" json:"count"`
+}
+
+func TestGetAzureResource(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + testSubscriptionID + "/xml":
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			fmt.Fprint(w, `<Resource xmlns="http://schemas.microsoft.com/windowsazure"><Name>xml</Name><Count>1</Count></Resource>`)
+		case "/" + testSubscriptionID + "/json":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			fmt.Fprint(w, `{"name":"json","count":2}`)
+		case "/" + testSubscriptionID + "/untyped":
+			w.Header()["Content-Type"] = nil
+			fmt.Fprint(w, ` {"name":"untyped","count":3}`)
+		default:
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<Error><Code>ResourceNotFound</Code><Message>gone</Message></Error>`)
+		}
+	}))
+
+	for _, want := range []testResource{{"xml", 1}, {"json", 2}, {"untyped", 3}} {
+		got, err := management.GetAzureResource[testResource](client, want.Name)
+		if err != nil {
+			t.Fatalf("GetAzureResource(%q)=%v", want.Name, err)
+		}
+		if got != want {
+			t.Errorf("GetAzureResource(%q): got %+v, want %+v", want.Name, got, want)
+		}
+	}
+	if _, err := management.GetAzureResource[testResource](client, "missing"); !management.IsResourceNotFoundError(err) {
+		t.Fatalf("GetAzureResource() of a missing resource: got error %v, want ResourceNotFound", err)
+	}
+
+	// Other implementations of Client are decoded as per the body alone.
+	wrapped := struct{ management.Client }{client}
+	got, err := management.GetAzureResource[testResource](wrapped, "xml")
+	if err != nil || got != (testResource{"xml", 1}) {
+		t.Fatalf("GetAzureResource() through a wrapped client: got %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,270 @@
+package management
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// numberOfRetries is how many times a failed request is retried by
+	// default.
+	numberOfRetries = 5
+
+	// maxRetryBackoff caps the delay between two consecutive retries.
+	maxRetryBackoff = time.Minute
+
+	// IdempotencyKeyHeader is the request header marking a request as safe
+	// to retry regardless of its method. See WithIdempotencyKey.
+	IdempotencyKeyHeader = "Idempotency-Key"
+)
+
+// RetryPolicy decides whether a failed request is sent again. The delay
+// between the attempts grows exponentially from ClientConfig.RetryBackoff,
+// capped at one minute, whatever the policy.
+type RetryPolicy interface {
+	// ShouldRetry reports whether req should be sent again after the given
+	// attempt, counted from zero, failed. statusCode is the HTTP status of
+	// the response, or zero if err is a transport error.
+	ShouldRetry(req *http.Request, statusCode int, err error, attempt int) bool
+}
+
+// DefaultRetryPolicy is the RetryPolicy used when ClientConfig.RetryPolicy
+// is nil. It retries transport errors and transient 408, 429, 500, 502, 503
+// and 504 responses, but only for the idempotent GET, HEAD, PUT and DELETE
+// requests, or for requests carrying an IdempotencyKeyHeader. Other methods,
+// notably POST, are not retried as this could duplicate their side effects.
+//
+// Timeouts follow the same rule: a net.Error timeout waiting for the
+// response is retried for the idempotent requests only, since a POST may
+// have been delivered and acted upon before it timed out.
+//
+// The exceptions are DNS resolution and connection failures, dial timeouts
+// included, which happen before the request reaches the server and are thus
+// retried for all methods, and TLS certificate errors, which are never
+// retried since they will not go away by themselves.
+type DefaultRetryPolicy struct {
+	// MaxRetries is the maximum number of times a request is retried.
+	MaxRetries int
+}
+
+// ShouldRetry implements RetryPolicy.
+func (p DefaultRetryPolicy) ShouldRetry(req *http.Request, statusCode int, err error, attempt int) bool {
+	if attempt >= p.MaxRetries {
+		return false
+	}
+	if statusCode == 0 {
+		switch {
+		case err == nil || isCertificateError(err):
+			return false
+		case isDialError(err):
+			return true
+		}
+		return isIdempotent(req)
+	}
+	if !isIdempotent(req) {
+		return false
+	}
+	switch statusCode {
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get(IdempotencyKeyHeader) != ""
+}
+
+// isDialError reports whether err occurred while resolving the address of
+// the server or connecting to it, i.e. before anything was sent.
+func isDialError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isCertificateError reports whether err is caused by a certificate which
+// failed verification during the TLS handshake.
+func isCertificateError(err error) bool {
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		invalid          x509.CertificateInvalidError
+		hostname         x509.HostnameError
+		verification     *tls.CertificateVerificationError
+	)
+	return errors.As(err, &unknownAuthority) ||
+		errors.As(err, &invalid) ||
+		errors.As(err, &hostname) ||
+		errors.As(err, &verification)
+}
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey returns a copy of ctx making the requests sent with it,
+// e.g. by PostAndWait, carry key in the IdempotencyKeyHeader. This tells
+// DefaultRetryPolicy that retrying them is safe, which the caller must
+// ensure, for instance because the API deduplicates requests by key.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+func idempotencyKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	return key
+}
+
+// RetryBudget bounds the retries of all the requests of a client within a
+// sliding window, so that sustained throttling does not pile up retries,
+// and the delays before them, across many concurrent calls. Once the budget
+// is spent, the failed requests are not retried and return their error
+// right away, until the older retries leave the window. The zero value sets
+// no budget.
+type RetryBudget struct {
+	// Window is the period over which the retries are counted. It must be
+	// positive if either limit is set.
+	Window time.Duration
+
+	// MaxRetries is the number of retries allowed within a Window. Zero
+	// means no limit.
+	MaxRetries int
+
+	// MaxDelay is the total backoff delay the retries may wait within a
+	// Window. Zero means no limit.
+	MaxDelay time.Duration
+}
+
+// enabled reports whether the budget limits the retries.
+func (b RetryBudget) enabled() bool {
+	return b.MaxRetries > 0 || b.MaxDelay > 0
+}
+
+// validate checks the budget is either disabled or usable.
+func (b RetryBudget) validate() error {
+	switch {
+	case b.MaxRetries < 0 || b.MaxDelay < 0:
+		return errors.New("azure: retry budget limits must not be negative")
+	case b.enabled() && b.Window <= 0:
+		return errors.New("azure: retry budget window must be a positive duration")
+	}
+	return nil
+}
+
+// budgetedRetry is a retry spent from the RetryBudget of a client.
+type budgetedRetry struct {
+	at    time.Time
+	delay time.Duration
+}
+
+// spendRetryBudget reports whether a retry waiting delay fits in the
+// RetryBudget of the client at now and, if so, spends it. The budget is
+// shared by all the copies of the client.
+func (client client) spendRetryBudget(delay time.Duration, now time.Time) bool {
+	budget := client.config.RetryBudget
+	if !budget.enabled() || client.state == nil {
+		return true
+	}
+	client.state.mu.Lock()
+	defer client.state.mu.Unlock()
+
+	retries := client.state.retries
+	for len(retries) > 0 && now.Sub(retries[0].at) >= budget.Window {
+		retries = retries[1:]
+	}
+	total := delay
+	for _, retry := range retries {
+		total += retry.delay
+	}
+	if (budget.MaxRetries > 0 && len(retries) >= budget.MaxRetries) || (budget.MaxDelay > 0 && total > budget.MaxDelay) {
+		client.state.retries = retries
+		return false
+	}
+	client.state.retries = append(retries, budgetedRetry{at: now, delay: delay})
+	return true
+}
+
+// retryPolicy returns the configured RetryPolicy, or the default one.
+func (client client) retryPolicy() RetryPolicy {
+	if client.config.RetryPolicy != nil {
+		return client.config.RetryPolicy
+	}
+	return DefaultRetryPolicy{MaxRetries: numberOfRetries}
+}
+
+// shouldRetry reports whether the given failed attempt at sending request
+// should be followed by another one and, if so, sleeps before it. It returns
+// ctx.Err() if ctx is done in the meantime.
+func (client client) shouldRetry(ctx context.Context, request *http.Request, body *requestBody, statusCode int, err error, attempt int) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	if !body.canRetry() || !client.retryPolicy().ShouldRetry(request, statusCode, err, attempt) {
+		client.errorf("azure: %s %s failed after %d attempt(s), not retrying: %v", request.Method, request.URL.Path, attempt+1, err)
+		return false, nil
+	}
+	delay := client.backoff(attempt)
+	if !client.spendRetryBudget(delay, time.Now()) {
+		client.errorf("azure: %s %s failed after %d attempt(s), retry budget exhausted: %v", request.Method, request.URL.Path, attempt+1, err)
+		return false, nil
+	}
+	client.infof("azure: %s %s failed, retrying in %v: %v", request.Method, request.URL.Path, delay, err)
+	if err := sleep(ctx, delay); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// backoff returns the delay before the given retry attempt, starting at zero.
+// The delay grows exponentially from the configured RetryBackoff up to
+// maxRetryBackoff and is jittered using the client's random source.
+func (client client) backoff(attempt int) time.Duration {
+	base := client.config.RetryBackoff
+	if base <= 0 {
+		return 0
+	}
+
+	d := maxRetryBackoff
+	if attempt < 32 {
+		if exp := base << uint(attempt); exp > 0 && exp < maxRetryBackoff {
+			d = exp
+		}
+	}
+	return time.Duration(client.config.Rand() * float64(d))
+}
+
+// seededRand is a pseudo-random source safe for concurrent use by the
+// requests of a single client without a lock: each call claims the next
+// state of a SplitMix64 generator with an atomic add, then mixes it.
+type seededRand struct {
+	state uint64
+}
+
+func newSeededRand(seed int64) *seededRand {
+	return &seededRand{state: uint64(seed)}
+}
+
+// Float64 returns a pseudo-random number in [0.0,1.0).
+func (r *seededRand) Float64() float64 {
+	z := atomic.AddUint64(&r.state, 0x9e3779b97f4a7c15)
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z ^= z >> 31
+	return float64(z>>11) / (1 << 53)
+}
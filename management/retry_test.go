@@ -0,0 +1,190 @@
+package management
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	c := client{config: ClientConfig{
+		RetryBackoff: time.Second,
+		Rand:         func() float64 { return 0.5 },
+	}}
+
+	testCases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 500 * time.Millisecond},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{6, maxRetryBackoff / 2},
+		{64, maxRetryBackoff / 2},
+	}
+	for i, testCase := range testCases {
+		if got := c.backoff(testCase.attempt); got != testCase.want {
+			t.Fatalf("Test %d: backoff(%d)=%v, want %v", i+1, testCase.attempt, got, testCase.want)
+		}
+	}
+}
+
+func TestBackoffDisabled(t *testing.T) {
+	c := client{}
+	if got := c.backoff(3); got != 0 {
+		t.Fatalf("backoff(3)=%v, want 0", got)
+	}
+}
+
+func TestBackoffSeededRand(t *testing.T) {
+	config := ClientConfig{RetryBackoff: time.Second}
+	a := client{config: config}
+	a.config.Rand = newSeededRand(42).Float64
+	b := client{config: config}
+	b.config.Rand = newSeededRand(42).Float64
+
+	for attempt := 0; attempt < numberOfRetries; attempt++ {
+		if da, db := a.backoff(attempt), b.backoff(attempt); da != db {
+			t.Fatalf("attempt %d: got %v and %v for equally seeded sources", attempt, da, db)
+		}
+	}
+}
+
+func TestSeededRandConcurrent(t *testing.T) {
+	r := newSeededRand(42)
+	const goroutines, draws = 8, 1000
+	values := make([][]float64, goroutines)
+	var wg sync.WaitGroup
+	for i := range values {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < draws; j++ {
+				values[i] = append(values[i], r.Float64())
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[float64]bool)
+	var sum float64
+	for _, vs := range values {
+		for _, v := range vs {
+			if v < 0 || v >= 1 {
+				t.Fatalf("got %v, want a number in [0.0,1.0)", v)
+			}
+			seen[v] = true
+			sum += v
+		}
+	}
+	if len(seen) != goroutines*draws {
+		t.Fatalf("got %d distinct numbers out of %d, want each state drawn once", len(seen), goroutines*draws)
+	}
+	if mean := sum / (goroutines * draws); mean < 0.45 || mean > 0.55 {
+		t.Fatalf("got mean %v, want about 0.5", mean)
+	}
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	policy := DefaultRetryPolicy{MaxRetries: 2}
+	responseTimeout := &url.Error{Op: "Post", URL: "https://management.core.windows.net/", Err: &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}}
+	dialTimeout := &url.Error{Op: "Post", URL: "https://management.core.windows.net/", Err: &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}}
+	newRequest := func(method, key string) *http.Request {
+		req, err := http.NewRequest(method, "https://management.core.windows.net/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		return req
+	}
+
+	testCases := []struct {
+		method, key string
+		statusCode  int
+		err         error
+		attempt     int
+		want        bool
+	}{
+		{"GET", "", http.StatusInternalServerError, nil, 0, true},
+		{"DELETE", "", http.StatusBadGateway, nil, 1, true},
+		{"PUT", "", http.StatusGatewayTimeout, nil, 0, true},
+		{"HEAD", "", http.StatusServiceUnavailable, nil, 0, true},
+		{"GET", "", 0, errors.New("connection reset"), 0, true},
+		{"GET", "", http.StatusInternalServerError, nil, 2, false},
+		{"GET", "", http.StatusNotFound, nil, 0, false},
+		{"GET", "", http.StatusBadRequest, nil, 0, false},
+		{"POST", "", http.StatusInternalServerError, nil, 0, false},
+		{"POST", "", 0, errors.New("connection reset"), 0, false},
+		{"POST", "key", http.StatusInternalServerError, nil, 0, true},
+		{"GET", "", 0, responseTimeout, 0, true},
+		{"POST", "", 0, responseTimeout, 0, false},
+		{"POST", "key", 0, responseTimeout, 0, true},
+		{"POST", "", 0, dialTimeout, 0, true},
+	}
+	for i, testCase := range testCases {
+		req := newRequest(testCase.method, testCase.key)
+		if got := policy.ShouldRetry(req, testCase.statusCode, testCase.err, testCase.attempt); got != testCase.want {
+			t.Errorf("Test %d: ShouldRetry(%s, %d, %v, %d)=%t, want %t", i+1, testCase.method,
+				testCase.statusCode, testCase.err, testCase.attempt, got, testCase.want)
+		}
+	}
+}
+
+func TestSpendRetryBudget(t *testing.T) {
+	c := client{
+		config: ClientConfig{RetryBudget: RetryBudget{Window: time.Minute, MaxRetries: 3, MaxDelay: 10 * time.Second}},
+		state:  &clientState{},
+	}
+	other := c // A copy shares the budget.
+	start := time.Date(2017, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		client client
+		delay  time.Duration
+		at     time.Duration
+		want   bool
+	}{
+		{c, time.Second, 0, true},
+		{other, 4 * time.Second, time.Second, true},
+		{c, 6 * time.Second, 2 * time.Second, false},
+		{other, 5 * time.Second, 3 * time.Second, true},
+		{c, 0, 4 * time.Second, false},
+		{c, 6 * time.Second, time.Minute, false},
+		{other, 5 * time.Second, time.Minute + time.Second, true},
+	}
+	for i, testCase := range testCases {
+		if got := testCase.client.spendRetryBudget(testCase.delay, start.Add(testCase.at)); got != testCase.want {
+			t.Errorf("Test %d: spendRetryBudget(%v) at +%v=%t, want %t", i+1, testCase.delay, testCase.at, got, testCase.want)
+		}
+	}
+
+	if !(client{}).spendRetryBudget(time.Hour, start) {
+		t.Fatal("a client without a budget refused to retry")
+	}
+}
+
+func TestRetryBudgetValidate(t *testing.T) {
+	testCases := []struct {
+		budget  RetryBudget
+		wantErr bool
+	}{
+		{RetryBudget{}, false},
+		{RetryBudget{Window: time.Minute, MaxRetries: 10}, false},
+		{RetryBudget{Window: time.Minute, MaxDelay: time.Minute}, false},
+		{RetryBudget{MaxRetries: 10}, true},
+		{RetryBudget{Window: time.Minute, MaxRetries: -1}, true},
+		{RetryBudget{Window: time.Minute, MaxDelay: -time.Second}, true},
+	}
+	for i, testCase := range testCases {
+		if err := testCase.budget.validate(); (err != nil) != testCase.wantErr {
+			t.Errorf("Test %d: validate(%+v)=%v, want error: %t", i+1, testCase.budget, err, testCase.wantErr)
+		}
+	}
+}
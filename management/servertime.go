@@ -0,0 +1,40 @@
+package management
+
+import (
+	"net/http"
+	"time"
+)
+
+func (client client) ServerTime() (time.Time, bool) {
+	if client.state == nil {
+		return time.Time{}, false
+	}
+	client.state.mu.Lock()
+	defer client.state.mu.Unlock()
+	return client.state.serverTime, !client.state.serverTime.IsZero()
+}
+
+func (client client) ClockSkew() (time.Duration, bool) {
+	if client.state == nil {
+		return 0, false
+	}
+	client.state.mu.Lock()
+	defer client.state.mu.Unlock()
+	if client.state.serverTime.IsZero() {
+		return 0, false
+	}
+	return client.state.serverTime.Sub(client.state.received), true
+}
+
+// recordServerTime stores the Date header of a response received at the
+// given local time. Missing or malformed headers are ignored, leaving the
+// previously recorded time in place.
+func (client client) recordServerTime(h http.Header, received time.Time) {
+	date, err := http.ParseTime(h.Get("Date"))
+	if err != nil {
+		return
+	}
+	client.state.mu.Lock()
+	client.state.serverTime, client.state.received = date, received
+	client.state.mu.Unlock()
+}
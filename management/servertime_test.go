@@ -0,0 +1,35 @@
+package management_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServerTime(t *testing.T) {
+	serverTime := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	dates := []string{serverTime.Format(http.TimeFormat), "garbage"}
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", dates[0])
+		dates = dates[1:]
+		w.Header().Set("x-ms-request-id", "op")
+	}))
+
+	if _, ok := client.ServerTime(); ok {
+		t.Fatal("expected no server time before the first response")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.SendAzureDeleteRequest("resource"); err != nil {
+			t.Fatal(err)
+		}
+		got, ok := client.ServerTime()
+		if !ok || !got.Equal(serverTime) {
+			t.Fatalf("Request %d: ServerTime()=%v, %t, want %v", i+1, got, ok, serverTime)
+		}
+		skew, ok := client.ClockSkew()
+		if !ok || skew < 59*time.Minute || skew > time.Hour {
+			t.Fatalf("Request %d: ClockSkew()=%v, %t, want about an hour", i+1, skew, ok)
+		}
+	}
+}
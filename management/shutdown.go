@@ -0,0 +1,98 @@
+package management
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrClientClosed is returned by the requests made, and the polls running,
+// after Shutdown has been called on the client.
+var ErrClientClosed = errors.New("azure: client is shut down")
+
+func (client client) Shutdown(ctx context.Context) error {
+	if client.state == nil {
+		return nil
+	}
+	client.state.mu.Lock()
+	if !client.state.closed {
+		client.state.closed = true
+		close(client.state.shutdownChan())
+	}
+	client.state.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		client.state.pollers.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// isClosed reports whether Shutdown has been called on the client.
+func (client client) isClosed() bool {
+	if client.state == nil {
+		return false
+	}
+	client.state.mu.Lock()
+	defer client.state.mu.Unlock()
+	return client.state.closed
+}
+
+// trackedPoll is an operation poll in progress, stopped through cancel.
+type trackedPoll struct {
+	operationID OperationID
+	cancel      context.CancelCauseFunc
+}
+
+// trackPoll registers a poll of the given operation with the client, so that
+// Shutdown waits for it and CancelAllOperations can stop it. The returned
+// context is cancelled with ErrClientClosed as its cause on shutdown, and
+// release must be called once the poll returns.
+func (client client) trackPoll(ctx context.Context, operationID OperationID) (_ context.Context, release func(), err error) {
+	if client.state == nil {
+		return ctx, func() {}, nil
+	}
+	client.state.mu.Lock()
+	if client.state.closed {
+		client.state.mu.Unlock()
+		return nil, nil, ErrClientClosed
+	}
+	client.state.pollers.Add(1)
+	shutdown := client.state.shutdownChan()
+	ctx, cancel := context.WithCancelCause(ctx)
+	poll := &trackedPoll{operationID: operationID, cancel: cancel}
+	if client.state.polls == nil {
+		client.state.polls = make(map[*trackedPoll]struct{})
+	}
+	client.state.polls[poll] = struct{}{}
+	client.state.mu.Unlock()
+
+	go func() {
+		select {
+		case <-shutdown:
+			cancel(ErrClientClosed)
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		cancel(nil)
+		client.state.mu.Lock()
+		delete(client.state.polls, poll)
+		client.state.mu.Unlock()
+		client.state.pollers.Done()
+	}, nil
+}
+
+// shutdownChan returns the channel closed by Shutdown, creating it if
+// needed. It must be called with mu held.
+func (state *clientState) shutdownChan() chan struct{} {
+	if state.shutdown == nil {
+		state.shutdown = make(chan struct{})
+	}
+	return state.shutdown
+}
@@ -0,0 +1,97 @@
+package testutils
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/management"
+)
+
+// FakeSubscriptionID is the subscription of the clients returned by
+// NewFakeOperationsClient.
+const FakeSubscriptionID = "00000000-0000-0000-0000-000000000000"
+
+// PendingStatuses returns a script for NewFakeOperationsClient reporting an
+// operation in progress for the given number of polls, then as final.
+func PendingStatuses(polls int, final management.OperationStatus) []management.OperationStatus {
+	statuses := make([]management.OperationStatus, 0, polls+1)
+	for i := 0; i < polls; i++ {
+		statuses = append(statuses, management.OperationStatusInProgress)
+	}
+	return append(statuses, final)
+}
+
+// NewFakeOperationsClient returns a management Client talking to a local
+// server instead of Azure, for testing code which starts asynchronous
+// operations and waits for them to complete.
+//
+// Every request which is not an operation status query is accepted and
+// starts a new operation. The statuses of each operation, as returned by
+// GetOperationStatus and observed by WaitForOperation, follow the given
+// script, one status per poll; the last status is repeated once the script
+// is exhausted. A Failed operation reports a FakeOperationFailed error. The
+// server is shut down when the test ends.
+func NewFakeOperationsClient(t *testing.T, statuses ...management.OperationStatus) management.Client {
+	if len(statuses) == 0 {
+		t.Fatal("testutils: at least one operation status required")
+	}
+
+	var (
+		mu    sync.Mutex
+		ops   int
+		polls = map[string]int{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		prefix := "/" + FakeSubscriptionID + "/operations/"
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			ops++
+			w.Header().Set("x-ms-request-id", fmt.Sprintf("op-%d", ops))
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, prefix)
+		n := polls[id]
+		polls[id]++
+		if n >= len(statuses) {
+			n = len(statuses) - 1
+		}
+		writeOperationStatus(w, id, statuses[n])
+	}))
+	t.Cleanup(srv.Close)
+
+	config := management.DefaultConfig()
+	config.ManagementURL = srv.URL
+	config.OperationPollInterval = time.Millisecond
+	config.RetryBackoff = time.Millisecond
+	client, err := management.NewClientWithAuthorizer(FakeSubscriptionID, management.BearerToken("fake"), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func writeOperationStatus(w http.ResponseWriter, id string, status management.OperationStatus) {
+	op := management.GetOperationStatusResponse{
+		ID:             id,
+		Status:         status,
+		HTTPStatusCode: "200",
+	}
+	if status == management.OperationStatusFailed {
+		op.HTTPStatusCode = "500"
+		op.Error = &management.AzureError{Code: "FakeOperationFailed", Message: "the operation failed"}
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	if err := xml.NewEncoder(w).Encode(op); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
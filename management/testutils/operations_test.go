@@ -0,0 +1,45 @@
+package testutils
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/management"
+)
+
+func TestFakeOperationsClient(t *testing.T) {
+	client := NewFakeOperationsClient(t, PendingStatuses(2, management.OperationStatusSucceeded)...)
+
+	id, err := client.SendAzurePutRequest("services/hostedservices/test", "", []byte("<Deployment/>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range PendingStatuses(2, management.OperationStatusSucceeded) {
+		op, err := client.GetOperationStatus(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op.Status != want {
+			t.Fatalf("Poll %d: got status %s, want %s", i+1, op.Status, want)
+		}
+	}
+
+	id, err = client.SendAzureDeleteRequest("services/hostedservices/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.WaitForOperation(id, nil); err != nil {
+		t.Fatalf("WaitForOperation()=%v", err)
+	}
+}
+
+func TestFakeOperationsClientFailed(t *testing.T) {
+	client := NewFakeOperationsClient(t, PendingStatuses(1, management.OperationStatusFailed)...)
+
+	id, err := client.SendAzurePostRequest("services/hostedservices/test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.WaitForOperation(id, nil); err == nil {
+		t.Fatal("expected the operation to fail")
+	}
+}
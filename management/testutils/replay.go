@@ -0,0 +1,242 @@
+package testutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+)
+
+// Interaction is a request sent to the management API along with the
+// response it got, as stored in a cassette.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the recorded part of a request.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the recorded part of a response.
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Cassette is the list of the interactions recorded by a ReplayTransport,
+// in the order they happened.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Redacted replaces the credentials scrubbed from the recorded interactions.
+const Redacted = "REDACTED"
+
+// scrubbedHeaders are the headers whose values are never recorded.
+var scrubbedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// pemBlock matches the PEM encoded certificates and keys, which are scrubbed
+// from the recorded bodies.
+var pemBlock = regexp.MustCompile(`(?s)-----BEGIN [A-Z ]+-----.*?-----END [A-Z ]+-----`)
+
+// ReplayTransport is an http.RoundTripper which records the interactions
+// with the management API into a cassette file, or replays them from it, for
+// reproducible tests and offline debugging. Set it as the Transport of the
+// ClientConfig.HTTPClient of the client under test; when recording, the
+// transport it wraps must present the management certificate.
+//
+// The cassette never holds credentials: the Authorization and cookie headers,
+// the PEM encoded certificates and keys of the bodies and the signature of
+// the URLs are replaced with Redacted. The management certificate itself is
+// only ever presented during the TLS handshake, so it is not recorded.
+//
+// A ReplayTransport is safe for concurrent use.
+type ReplayTransport struct {
+	path      string
+	transport http.RoundTripper // nil when replaying
+
+	mu       sync.Mutex
+	cassette Cassette
+	replayed []bool
+}
+
+// NewRecorder returns a ReplayTransport sending the requests through
+// transport, or http.DefaultTransport if nil, and recording them along with
+// their responses. Call Save to write the cassette to path.
+func NewRecorder(path string, transport http.RoundTripper) *ReplayTransport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &ReplayTransport{path: path, transport: transport}
+}
+
+// NewReplayer returns a ReplayTransport replaying the interactions of the
+// cassette at path. Each recorded interaction answers a single request with
+// the same method and URL, in the order they were recorded; a request which
+// matches none fails without reaching the network.
+func NewReplayer(path string) (*ReplayTransport, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("testutils: invalid cassette %s: %v", path, err)
+	}
+	return &ReplayTransport{
+		path:     path,
+		cassette: cassette,
+		replayed: make([]bool, len(cassette.Interactions)),
+	}, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if t.transport == nil {
+		return t.replay(req)
+	}
+
+	// The request may not be modified, send a copy carrying the body read.
+	out := req.Clone(req.Context())
+	if req.Body != nil {
+		out.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	resp, err := t.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cassette.Interactions = append(t.cassette.Interactions, Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    scrubURL(req.URL),
+			Header: scrubHeader(req.Header),
+			Body:   scrubBody(body),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     scrubHeader(resp.Header),
+			Body:       scrubBody(respBody),
+		},
+	})
+	return resp, nil
+}
+
+// replay answers req with the first matching interaction not replayed yet.
+func (t *ReplayTransport) replay(req *http.Request) (*http.Response, error) {
+	requestURL := scrubURL(req.URL)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, interaction := range t.cassette.Interactions {
+		if t.replayed[i] || interaction.Request.Method != req.Method || interaction.Request.URL != requestURL {
+			continue
+		}
+		t.replayed[i] = true
+		recorded := interaction.Response
+		header := recorded.Header
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+			StatusCode:    recorded.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header.Clone(),
+			Body:          ioutil.NopCloser(bytes.NewReader([]byte(recorded.Body))),
+			ContentLength: int64(len(recorded.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("testutils: no recorded interaction left for %s %s in %s", req.Method, requestURL, t.path)
+}
+
+// Cassette returns a copy of the interactions recorded so far.
+func (t *ReplayTransport) Cassette() Cassette {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return Cassette{Interactions: append([]Interaction(nil), t.cassette.Interactions...)}
+}
+
+// Save writes the recorded interactions to the cassette file.
+func (t *ReplayTransport) Save() error {
+	data, err := json.MarshalIndent(t.Cassette(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(t.path, append(data, '\n'), 0644)
+}
+
+// Unreplayed returns the number of the recorded interactions which have not
+// been replayed, e.g. to check that the code under test sent every request
+// it was recorded sending.
+func (t *ReplayTransport) Unreplayed() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var n int
+	for _, replayed := range t.replayed {
+		if !replayed {
+			n++
+		}
+	}
+	return n
+}
+
+func scrubHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	scrubbed := header.Clone()
+	for _, key := range scrubbedHeaders {
+		if _, ok := scrubbed[key]; ok {
+			scrubbed[key] = []string{Redacted}
+		}
+	}
+	return scrubbed
+}
+
+func scrubBody(body []byte) string {
+	return string(pemBlock.ReplaceAll(body, []byte(Redacted)))
+}
+
+// scrubURL returns the URL with the signature of its query, if any, redacted,
+// as is done for the shared access signatures of storage URLs.
+func scrubURL(u *url.URL) string {
+	query := u.Query()
+	if _, ok := query["sig"]; !ok {
+		return u.String()
+	}
+	query.Set("sig", Redacted)
+	scrubbed := *u
+	scrubbed.RawQuery = query.Encode()
+	return scrubbed.String()
+}
@@ -0,0 +1,87 @@
+package management
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+)
+
+// requestTrace records the timings of the phases of a request, see
+// ClientConfig.EnableTrace. The hooks may be called concurrently, e.g. when
+// dialing several addresses at once.
+type requestTrace struct {
+	mu sync.Mutex
+
+	start                      time.Time
+	dnsStart, connectStart     time.Time
+	tlsStart                   time.Time
+	gotConn, gotFirstByte      time.Time
+	dns, connect, tlsHandshake time.Duration
+	reused                     bool
+}
+
+// withTrace returns ctx with trace hooks recording into t. Any trace hooks
+// already in ctx are called as well.
+func (t *requestTrace) withTrace(ctx context.Context) context.Context {
+	t.start = time.Now()
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.record(func() { t.dnsStart = time.Now() }) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.record(func() { t.dns = time.Since(t.dnsStart) }) },
+		ConnectStart: func(string, string) {
+			t.record(func() {
+				if t.connectStart.IsZero() {
+					t.connectStart = time.Now()
+				}
+			})
+		},
+		ConnectDone: func(string, string, error) {
+			t.record(func() { t.connect = time.Since(t.connectStart) })
+		},
+		TLSHandshakeStart: func() { t.record(func() { t.tlsStart = time.Now() }) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.record(func() { t.tlsHandshake = time.Since(t.tlsStart) })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.record(func() { t.gotConn, t.reused = time.Now(), info.Reused })
+		},
+		GotFirstResponseByte: func() { t.record(func() { t.gotFirstByte = time.Now() }) },
+	})
+}
+
+func (t *requestTrace) record(f func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	f()
+}
+
+// String formats the recorded phase durations. The phases which did not
+// happen, e.g. the DNS lookup and the dial on a reused connection, are
+// omitted.
+func (t *requestTrace) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var phases []string
+	add := func(name string, d time.Duration) {
+		if d > 0 {
+			phases = append(phases, fmt.Sprintf("%s=%v", name, d))
+		}
+	}
+	add("dns", t.dns)
+	add("connect", t.connect)
+	add("tls", t.tlsHandshake)
+	if !t.gotConn.IsZero() {
+		add("conn", t.gotConn.Sub(t.start))
+	}
+	if !t.gotFirstByte.IsZero() {
+		add("first byte", t.gotFirstByte.Sub(t.start))
+	}
+	if t.reused {
+		phases = append(phases, "reused")
+	}
+	return strings.Join(phases, " ")
+}
@@ -1,11 +1,55 @@
 package management
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"time"
 )
 
-func getResponseBody(response *http.Response) ([]byte, error) {
+// getResponseBody reads and closes the body of the response. If max is
+// positive, reading a larger body fails with ErrResponseTooLarge once max
+// bytes have been read.
+func getResponseBody(response *http.Response, max int64) ([]byte, error) {
 	defer response.Body.Close()
-	return ioutil.ReadAll(response.Body)
+	if max <= 0 {
+		return ioutil.ReadAll(response.Body)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, max+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > max {
+		return nil, fmt.Errorf("%w: read %d bytes, at most %d allowed", ErrResponseTooLarge, len(body), max)
+	}
+	return body, nil
+}
+
+// sleep pauses for the duration d or until ctx is done, whichever happens
+// first. It returns ctx.Err() in the latter case.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// firstNonEmpty returns the first of the given strings which is not empty.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
 }
@@ -0,0 +1,73 @@
+package management_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/management"
+)
+
+const testSubscriptionID = "00000000-0000-0000-0000-000000000000"
+
+// newTestCert returns a PEM-encoded self-signed certificate and private key
+// valid between notBefore and notAfter.
+func newTestCert(t *testing.T, notBefore, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "azure-sdk-for-go test"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return append(cert, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})...)
+}
+
+// newTestClient starts a test server with the given handler and returns a
+// client configured to talk to it. The server is closed when the test ends.
+func newTestClient(t *testing.T, handler http.Handler) management.Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return newTestClientFromConfig(t, newTestConfig(srv.URL))
+}
+
+// newTestConfig returns a client configuration for talking to a test server
+// at the given URL, with polling and retry delays shortened.
+func newTestConfig(url string) management.ClientConfig {
+	config := management.DefaultConfig()
+	config.ManagementURL = url
+	config.OperationPollInterval = time.Millisecond
+	config.RetryBackoff = time.Millisecond
+	return config
+}
+
+// newTestClientFromConfig returns a client with a fresh test certificate.
+func newTestClientFromConfig(t *testing.T, config management.ClientConfig) management.Client {
+	cert := newTestCert(t, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	client, err := management.NewClientFromConfig(testSubscriptionID, cert, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}